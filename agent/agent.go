@@ -39,6 +39,8 @@ func (a *Agent) Run(ctx context.Context) error {
 		return ctx.Err()
 	}
 
+	models.SetTraceFilter(a.Config.Trace)
+
 	log.Printf("D! [agent] Connecting outputs")
 	err := a.connectOutputs(ctx)
 	if err != nil {
@@ -48,12 +50,35 @@ func (a *Agent) Run(ctx context.Context) error {
 	inputC := make(chan telegraf.Metric, 100)
 	procC := make(chan telegraf.Metric, 100)
 	outputC := make(chan telegraf.Metric, 100)
+	aggC := make(chan telegraf.Metric, 100)
+	pushC := aggC
 
 	startTime := time.Now()
 
+	// Processors are started before the inputs, the returned channel is the
+	// head of the processor chain.
+	var procUnits, aggProcUnits []*processorUnit
+	if len(a.Config.Processors) > 0 {
+		log.Printf("D! [agent] Starting processors")
+		inputC, procUnits, err = a.startProcessors(procC, a.Config.Processors)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(a.Config.Aggregators) > 0 && len(a.Config.AggProcessors) > 0 {
+		pushC, aggProcUnits, err = a.startProcessors(aggC, a.Config.AggProcessors)
+		if err != nil {
+			a.stopProcessors(procUnits)
+			return err
+		}
+	}
+
 	log.Printf("D! [agent] Starting service inputs")
 	err = a.startServiceInputs(ctx, inputC)
 	if err != nil {
+		a.stopProcessors(procUnits)
+		a.stopProcessors(aggProcUnits)
 		return err
 	}
 
@@ -84,16 +109,15 @@ func (a *Agent) Run(ctx context.Context) error {
 		dst = procC
 
 		wg.Add(1)
-		go func(src, dst chan telegraf.Metric) {
+		go func() {
 			defer wg.Done()
 
-			err := a.runProcessors(src, dst)
+			err := a.runProcessors(procUnits)
 			if err != nil {
 				log.Printf("E! [agent] Error running processors: %v", err)
 			}
-			close(dst)
 			log.Printf("D! [agent] Processor channel closed")
-		}(src, dst)
+		}()
 
 		src = dst
 	}
@@ -105,7 +129,7 @@ func (a *Agent) Run(ctx context.Context) error {
 		go func(src, dst chan telegraf.Metric) {
 			defer wg.Done()
 
-			err := a.runAggregators(startTime, src, dst)
+			err := a.runAggregators(startTime, src, dst, pushC, aggC, aggProcUnits)
 			if err != nil {
 				log.Printf("E! [agent] Error running aggregators: %v", err)
 			}
@@ -137,6 +161,8 @@ func (a *Agent) Run(ctx context.Context) error {
 
 // Test runs the inputs once and prints the output to stdout in line protocol.
 func (a *Agent) Test(ctx context.Context) error {
+	models.SetTraceFilter(a.Config.Trace)
+
 	var wg sync.WaitGroup
 	metricC := make(chan telegraf.Metric)
 	nulC := make(chan telegraf.Metric)
@@ -313,30 +339,91 @@ func (a *Agent) gatherOnce(
 	}
 }
 
-// runProcessors applies processors to metrics.
-func (a *Agent) runProcessors(
-	src <-chan telegraf.Metric,
-	agg chan<- telegraf.Metric,
-) error {
-	for metric := range src {
-		metrics := a.applyProcessors(metric)
+// processorUnit is a single processor in a chain of processors, metrics
+// are read from src and the processed metrics are sent to dst.
+type processorUnit struct {
+	src       chan telegraf.Metric
+	dst       chan telegraf.Metric
+	processor *models.RunningProcessor
+}
+
+// startProcessors starts a chain of processors ending in dst and returns the
+// channel at the head of the chain.  If any processor fails to start the
+// already started processors are stopped.
+func (a *Agent) startProcessors(
+	dst chan telegraf.Metric,
+	processors models.RunningProcessors,
+) (chan telegraf.Metric, []*processorUnit, error) {
+	var units []*processorUnit
+
+	// The chain is built from the last processor so that each processor can
+	// be started with the channel of the processor after it.
+	for i := len(processors) - 1; i >= 0; i-- {
+		processor := processors[i]
+
+		acc := NewAccumulator(processor, dst)
+		err := processor.Start(acc)
+		if err != nil {
+			log.Printf("E! [agent] Processor %s failed to start: %v",
+				processor.Name(), err)
+			a.stopProcessors(units)
+			return nil, nil, err
+		}
 
-		for _, metric := range metrics {
-			agg <- metric
+		src := make(chan telegraf.Metric, 100)
+		unit := &processorUnit{
+			src:       src,
+			dst:       dst,
+			processor: processor,
 		}
+		units = append([]*processorUnit{unit}, units...)
+		dst = src
 	}
 
-	return nil
+	return dst, units, nil
+}
+
+// stopProcessors stops processors that have been started but never run.
+func (a *Agent) stopProcessors(units []*processorUnit) {
+	for _, unit := range units {
+		err := unit.processor.Stop()
+		if err != nil {
+			log.Printf("E! [agent] Error stopping processor %s: %v",
+				unit.processor.Name(), err)
+		}
+	}
 }
 
-// applyProcessors applies all processors to a metric.
-func (a *Agent) applyProcessors(m telegraf.Metric) []telegraf.Metric {
-	metrics := []telegraf.Metric{m}
-	for _, processor := range a.Config.Processors {
-		metrics = processor.Apply(metrics...)
+// runProcessors adds metrics to a chain of started processors.
+//
+// Each processor runs until its src is closed, it is then stopped and its dst
+// is closed.  Returns once all processors have been stopped.
+func (a *Agent) runProcessors(units []*processorUnit) error {
+	var wg sync.WaitGroup
+	for _, unit := range units {
+		wg.Add(1)
+		go func(unit *processorUnit) {
+			defer wg.Done()
+
+			acc := NewAccumulator(unit.processor, unit.dst)
+			for metric := range unit.src {
+				err := unit.processor.Add(metric, acc)
+				if err != nil {
+					acc.AddError(err)
+					metric.Drop()
+				}
+			}
+
+			err := unit.processor.Stop()
+			if err != nil {
+				acc.AddError(err)
+			}
+			close(unit.dst)
+		}(unit)
 	}
+	wg.Wait()
 
-	return metrics
+	return nil
 }
 
 func updateWindow(start time.Time, roundInterval bool, period time.Duration) (time.Time, time.Time) {
@@ -358,12 +445,20 @@ func updateWindow(start time.Time, roundInterval bool, period time.Duration) (ti
 // runAggregators adds metrics to the aggregators and triggers their periodic
 // push call.
 //
+// Aggregations are pushed to pushC, the head of the chain of processor units
+// applied to aggregations, and are forwarded to dst once they are read from
+// aggC at the end of the chain.  When there are no units pushC and aggC are
+// the same channel.
+//
 // Runs until src is closed and all metrics have been processed.  Will call
 // push one final time before returning.
 func (a *Agent) runAggregators(
 	startTime time.Time,
 	src <-chan telegraf.Metric,
 	dst chan<- telegraf.Metric,
+	pushC chan<- telegraf.Metric,
+	aggC <-chan telegraf.Metric,
+	units []*processorUnit,
 ) error {
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -395,7 +490,18 @@ func (a *Agent) runAggregators(
 		cancel()
 	}()
 
-	aggregations := make(chan telegraf.Metric, 100)
+	if len(units) > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := a.runProcessors(units)
+			if err != nil {
+				log.Printf("E! [agent] Error running aggregator processors: %v", err)
+			}
+		}()
+	}
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -406,21 +512,18 @@ func (a *Agent) runAggregators(
 			go func(agg *models.RunningAggregator) {
 				defer aggWg.Done()
 
-				acc := NewAccumulator(agg, aggregations)
+				acc := NewAccumulator(agg, pushC)
 				acc.SetPrecision(a.Precision())
 				a.push(ctx, agg, acc)
 			}(agg)
 		}
 
 		aggWg.Wait()
-		close(aggregations)
+		close(pushC)
 	}()
 
-	for metric := range aggregations {
-		metrics := a.applyProcessors(metric)
-		for _, metric := range metrics {
-			dst <- metric
-		}
+	for metric := range aggC {
+		dst <- metric
 	}
 
 	wg.Wait()
@@ -489,9 +592,26 @@ func (a *Agent) runOutputs(
 		}(output)
 	}
 
+	outputs := make([]*models.RunningOutput, 0, len(a.Config.Outputs))
 	for metric := range src {
-		for i, output := range a.Config.Outputs {
-			if i == len(a.Config.Outputs)-1 {
+		// Only the outputs subscribed to the metric's route receive it.
+		outputs = outputs[:0]
+		for _, output := range a.Config.Outputs {
+			if output.Config.Filter.SelectRoute(metric) {
+				outputs = append(outputs, output)
+			} else {
+				models.Trace("outputs."+output.Name, metric,
+					"not written, not subscribed to the metric's route")
+			}
+		}
+
+		if len(outputs) == 0 {
+			metric.Drop()
+			continue
+		}
+
+		for i, output := range outputs {
+			if i == len(outputs)-1 {
 				output.AddMetric(metric)
 			} else {
 				output.AddMetric(metric.Copy())
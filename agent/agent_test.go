@@ -1,12 +1,17 @@
 package agent
 
 import (
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/internal/config"
+	"github.com/influxdata/telegraf/internal/models"
 	_ "github.com/influxdata/telegraf/plugins/inputs/all"
 	_ "github.com/influxdata/telegraf/plugins/outputs/all"
+	"github.com/influxdata/telegraf/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -108,6 +113,87 @@ func TestAgent_LoadOutput(t *testing.T) {
 	assert.Equal(t, 3, len(a.Config.Outputs))
 }
 
+// MockOutput is an Output that records the metrics written to it.
+type MockOutput struct {
+	sync.Mutex
+	metrics []telegraf.Metric
+}
+
+func (o *MockOutput) Connect() error       { return nil }
+func (o *MockOutput) Close() error         { return nil }
+func (o *MockOutput) Description() string  { return "" }
+func (o *MockOutput) SampleConfig() string { return "" }
+
+func (o *MockOutput) Write(metrics []telegraf.Metric) error {
+	o.Lock()
+	defer o.Unlock()
+	o.metrics = append(o.metrics, metrics...)
+	return nil
+}
+
+func (o *MockOutput) Metrics() []telegraf.Metric {
+	o.Lock()
+	defer o.Unlock()
+	return o.metrics
+}
+
+func TestAgent_RunOutputsRoutes(t *testing.T) {
+	c := config.NewConfig()
+	c.Agent.RoundInterval = false
+	c.Agent.FlushInterval = internal.Duration{Duration: time.Hour}
+
+	newOutput := func(name string, routes ...string) *MockOutput {
+		filter := models.Filter{Routes: routes}
+		require.NoError(t, filter.Compile())
+
+		output := &MockOutput{}
+		c.Outputs = append(c.Outputs, models.NewRunningOutput(name, output,
+			&models.OutputConfig{Name: name, Filter: filter}, 0, 0))
+		return output
+	}
+	defaultOutput := newOutput("default")
+	otherOutput := newOutput("other", "other")
+	allOutput := newOutput("all", "*")
+
+	a, err := NewAgent(c)
+	require.NoError(t, err)
+
+	src := make(chan telegraf.Metric, 10)
+	src <- testutil.MustMetric("untagged",
+		map[string]string{},
+		map[string]interface{}{"value": 1},
+		time.Unix(0, 0))
+	src <- testutil.MustMetric("routed",
+		map[string]string{models.RouteTag: "other"},
+		map[string]interface{}{"value": 2},
+		time.Unix(0, 0))
+	src <- testutil.MustMetric("unsubscribed",
+		map[string]string{models.RouteTag: "third"},
+		map[string]interface{}{"value": 3},
+		time.Unix(0, 0))
+	close(src)
+
+	err = a.runOutputs(time.Now(), src)
+	require.NoError(t, err)
+
+	untagged := testutil.MustMetric("untagged",
+		map[string]string{},
+		map[string]interface{}{"value": 1},
+		time.Unix(0, 0))
+	routed := testutil.MustMetric("routed",
+		map[string]string{},
+		map[string]interface{}{"value": 2},
+		time.Unix(0, 0))
+	unsubscribed := testutil.MustMetric("unsubscribed",
+		map[string]string{},
+		map[string]interface{}{"value": 3},
+		time.Unix(0, 0))
+
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{untagged}, defaultOutput.Metrics())
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{routed}, otherOutput.Metrics())
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{untagged, routed, unsubscribed}, allOutput.Metrics(), testutil.SortMetrics())
+}
+
 func TestWindow(t *testing.T) {
 	parse := func(s string) time.Time {
 		tm, err := time.Parse(time.RFC3339, s)
@@ -66,6 +66,18 @@ type Config struct {
 	Aggregators []*models.RunningAggregator
 	// Processors have a slice wrapper type because they need to be sorted
 	Processors models.RunningProcessors
+	// AggProcessors are a separate instance of each processor which are
+	// applied to the metrics emitted by the aggregators.  They are only
+	// created when aggregators are configured.
+	AggProcessors models.RunningProcessors
+
+	// aggProcessorDefs holds the processors that have no instance in
+	// AggProcessors yet, as no aggregators have been configured so far.
+	aggProcessorDefs []*processorDef
+
+	// Trace selects the metrics to log as they pass through the plugins, it
+	// is nil when tracing is disabled.
+	Trace *models.Filter
 }
 
 func NewConfig() *Config {
@@ -82,6 +94,7 @@ func NewConfig() *Config {
 		Inputs:        make([]*models.RunningInput, 0),
 		Outputs:       make([]*models.RunningOutput, 0),
 		Processors:    make([]*models.RunningProcessor, 0),
+		AggProcessors: make([]*models.RunningProcessor, 0),
 		InputFilters:  make([]string, 0),
 		OutputFilters: make([]string, 0),
 	}
@@ -130,6 +143,17 @@ type AgentConfig struct {
 	// not be less than 2 times MetricBatchSize.
 	MetricBufferLimit int
 
+	// MetricBufferLimitBytes is the max estimated size of the metrics that
+	// each output plugin will cache.  When exceeded, the oldest metrics will
+	// be dropped.  A value of 0 is unlimited.
+	MetricBufferLimitBytes internal.Size `toml:"metric_buffer_limit_bytes"`
+
+	// MetricBufferTotalLimitBytes is the max estimated size of the metrics
+	// cached by all output plugins combined.  When exceeded, the oldest
+	// metrics of the output being added to will be dropped.  A value of 0 is
+	// unlimited.
+	MetricBufferTotalLimitBytes internal.Size `toml:"metric_buffer_total_limit_bytes"`
+
 	// FlushBufferWhenFull tells Telegraf to flush the metric buffer whenever
 	// it fills up, regardless of FlushInterval. Setting this option to true
 	// does _not_ deactivate FlushInterval.
@@ -187,7 +211,7 @@ func (c *Config) AggregatorNames() []string {
 func (c *Config) ProcessorNames() []string {
 	var name []string
 	for _, processor := range c.Processors {
-		name = append(name, processor.Name)
+		name = append(name, processor.Config.Name)
 	}
 	return name
 }
@@ -257,6 +281,12 @@ var agentConfig = `
   ## Maximum number of unwritten metrics per output.
   metric_buffer_limit = 10000
 
+  ## Maximum estimated size of unwritten metrics per output, and of unwritten
+  ## metrics across all outputs.  When exceeded the oldest metrics are dropped.
+  ## A value of 0 is unlimited.
+  # metric_buffer_limit_bytes = "64MB"
+  # metric_buffer_total_limit_bytes = "256MB"
+
   ## Collection jitter is used to jitter the collection by a random amount.
   ## Each plugin will sleep for a random time within jitter before collecting.
   ## This can be used to avoid many plugins querying things like sysfs at the
@@ -720,6 +750,15 @@ func (c *Config) LoadConfig(path string) error {
 
 		switch name {
 		case "agent", "global_tags", "tags":
+		case "trace":
+			f, err := buildFilter(subTable)
+			if err == nil {
+				err = checkNoRoutes(f)
+			}
+			if err != nil {
+				return fmt.Errorf("Error parsing %s, %s", path, err)
+			}
+			c.Trace = &f
 		case "outputs":
 			for pluginName, pluginVal := range subTable.Fields {
 				switch pluginSubTable := pluginVal.(type) {
@@ -799,6 +838,22 @@ func (c *Config) LoadConfig(path string) error {
 		sort.Sort(c.Processors)
 	}
 
+	// Aggregators may be defined after the processors or in another file, so
+	// the instances for aggregations are created once all plugins are known.
+	if len(c.Aggregators) > 0 {
+		for _, def := range c.aggProcessorDefs {
+			rf, err := def.newRunningProcessor()
+			if err != nil {
+				return fmt.Errorf("Error parsing %s, %s", path, err)
+			}
+			c.AggProcessors = append(c.AggProcessors, rf)
+		}
+		c.aggProcessorDefs = nil
+	}
+	if len(c.AggProcessors) > 1 {
+		sort.Sort(c.AggProcessors)
+	}
+
 	return nil
 }
 
@@ -908,27 +963,104 @@ func (c *Config) addProcessor(name string, table *ast.Table) error {
 	if !ok {
 		return fmt.Errorf("Undefined but requested processor: %s", name)
 	}
-	processor := creator()
 
 	processorConfig, err := buildProcessor(name, table)
 	if err != nil {
 		return err
 	}
 
-	if err := toml.UnmarshalTable(table, processor); err != nil {
-		return err
+	// The parser and serializer options are removed from the table as they
+	// are read, so they are read once and shared by both instances.
+	var parserConfig *parsers.Config
+	var serializerConfig *serializers.Config
+	plugin := unwrapProcessor(creator())
+	if _, ok := plugin.(serializers.SerializerOutput); ok {
+		serializerConfig, err = getSerializerConfig(name, table)
+		if err != nil {
+			return err
+		}
+	}
+	if _, ok := plugin.(parsers.ParserInput); ok {
+		parserConfig, err = getParserConfig(name, table)
+		if err != nil {
+			return err
+		}
+		// Both use the data_format option, which the serializer has already
+		// removed from the table.
+		if serializerConfig != nil {
+			parserConfig.DataFormat = serializerConfig.DataFormat
+		}
 	}
 
-	rf := &models.RunningProcessor{
-		Name:      name,
-		Processor: processor,
-		Config:    processorConfig,
+	def := &processorDef{
+		creator:          creator,
+		processorConfig:  processorConfig,
+		parserConfig:     parserConfig,
+		serializerConfig: serializerConfig,
+		table:            table,
 	}
 
+	rf, err := def.newRunningProcessor()
+	if err != nil {
+		return err
+	}
 	c.Processors = append(c.Processors, rf)
+
+	// Aggregations are run through a separate instance of each processor so
+	// that stateful processors do not mix the two streams.  The instance is
+	// created by LoadConfig if any aggregators are configured.
+	c.aggProcessorDefs = append(c.aggProcessorDefs, def)
 	return nil
 }
 
+// processorDef holds everything needed to create an instance of a configured
+// processor.
+type processorDef struct {
+	creator          processors.StreamingCreator
+	processorConfig  *models.ProcessorConfig
+	parserConfig     *parsers.Config
+	serializerConfig *serializers.Config
+	table            *ast.Table
+}
+
+// unwrapProcessor returns the original processor of wrapped processors, which
+// is the one that is configured.
+func unwrapProcessor(processor telegraf.StreamingProcessor) interface{} {
+	if p, ok := processor.(processors.Unwrapper); ok {
+		return p.Unwrap()
+	}
+	return processor
+}
+
+func (def *processorDef) newRunningProcessor() (*models.RunningProcessor, error) {
+	processor := def.creator()
+	plugin := unwrapProcessor(processor)
+
+	switch t := plugin.(type) {
+	case serializers.SerializerOutput:
+		serializer, err := serializers.NewSerializer(def.serializerConfig)
+		if err != nil {
+			return nil, err
+		}
+		t.SetSerializer(serializer)
+	}
+
+	switch t := plugin.(type) {
+	case parsers.ParserInput:
+		parser, err := parsers.NewParser(def.parserConfig)
+		if err != nil {
+			return nil, err
+		}
+		t.SetParser(parser)
+	}
+
+	if err := toml.UnmarshalTable(def.table, plugin); err != nil {
+		return nil, err
+	}
+
+	return models.NewRunningProcessor(processor, def.processorConfig), nil
+}
+
 func (c *Config) addOutput(name string, table *ast.Table) error {
 	if len(c.OutputFilters) > 0 && !sliceContains(name, c.OutputFilters) {
 		return nil
@@ -959,6 +1091,11 @@ func (c *Config) addOutput(name string, table *ast.Table) error {
 		return err
 	}
 
+	if outputConfig.MetricBufferLimitBytes == 0 {
+		outputConfig.MetricBufferLimitBytes = c.Agent.MetricBufferLimitBytes.Size
+	}
+	outputConfig.MetricBufferTotalLimitBytes = c.Agent.MetricBufferTotalLimitBytes.Size
+
 	ro := models.NewRunningOutput(name, output, outputConfig,
 		c.Agent.MetricBatchSize, c.Agent.MetricBufferLimit)
 	c.Outputs = append(c.Outputs, ro)
@@ -1089,6 +1226,14 @@ func buildAggregator(name string, tbl *ast.Table) (*models.AggregatorConfig, err
 		}
 	}
 
+	if node, ok := tbl.Fields["route"]; ok {
+		if kv, ok := node.(*ast.KeyValue); ok {
+			if str, ok := kv.Value.(*ast.String); ok {
+				conf.Route = str.Value
+			}
+		}
+	}
+
 	conf.Tags = make(map[string]string)
 	if node, ok := tbl.Fields["tags"]; ok {
 		if subtbl, ok := node.(*ast.Table); ok {
@@ -1104,12 +1249,16 @@ func buildAggregator(name string, tbl *ast.Table) (*models.AggregatorConfig, err
 	delete(tbl.Fields, "name_prefix")
 	delete(tbl.Fields, "name_suffix")
 	delete(tbl.Fields, "name_override")
+	delete(tbl.Fields, "route")
 	delete(tbl.Fields, "tags")
 	var err error
 	conf.Filter, err = buildFilter(tbl)
 	if err != nil {
 		return conf, err
 	}
+	if err := checkNoRoutes(conf.Filter); err != nil {
+		return conf, err
+	}
 	return conf, nil
 }
 
@@ -1131,17 +1280,29 @@ func buildProcessor(name string, tbl *ast.Table) (*models.ProcessorConfig, error
 		}
 	}
 
+	if node, ok := tbl.Fields["route"]; ok {
+		if kv, ok := node.(*ast.KeyValue); ok {
+			if str, ok := kv.Value.(*ast.String); ok {
+				conf.Route = str.Value
+			}
+		}
+	}
+
 	delete(tbl.Fields, "order")
+	delete(tbl.Fields, "route")
 	var err error
 	conf.Filter, err = buildFilter(tbl)
 	if err != nil {
 		return conf, err
 	}
+	if err := checkNoRoutes(conf.Filter); err != nil {
+		return conf, err
+	}
 	return conf, nil
 }
 
 // buildFilter builds a Filter
-// (tagpass/tagdrop/namepass/namedrop/fieldpass/fielddrop) to
+// (tagpass/tagdrop/namepass/namedrop/fieldpass/fielddrop/routes) to
 // be inserted into the models.OutputConfig/models.InputConfig
 // to be used for glob filtering on tags and measurements
 func buildFilter(tbl *ast.Table) (models.Filter, error) {
@@ -1260,6 +1421,17 @@ func buildFilter(tbl *ast.Table) (models.Filter, error) {
 			}
 		}
 	}
+	if node, ok := tbl.Fields["routes"]; ok {
+		if kv, ok := node.(*ast.KeyValue); ok {
+			if ary, ok := kv.Value.(*ast.Array); ok {
+				for _, elem := range ary.Value {
+					if str, ok := elem.(*ast.String); ok {
+						f.Routes = append(f.Routes, str.Value)
+					}
+				}
+			}
+		}
+	}
 	if err := f.Compile(); err != nil {
 		return f, err
 	}
@@ -1274,9 +1446,19 @@ func buildFilter(tbl *ast.Table) (models.Filter, error) {
 	delete(tbl.Fields, "tagpass")
 	delete(tbl.Fields, "tagexclude")
 	delete(tbl.Fields, "taginclude")
+	delete(tbl.Fields, "routes")
 	return f, nil
 }
 
+// checkNoRoutes returns an error if the filter has routes set, only outputs
+// subscribe to routes.
+func checkNoRoutes(f models.Filter) error {
+	if len(f.Routes) > 0 {
+		return fmt.Errorf("routes can only be set on outputs, use route to assign metrics to a route")
+	}
+	return nil
+}
+
 // buildInput parses input specific items from the ast.Table,
 // builds the filter and returns a
 // models.InputConfig to be inserted into models.RunningInput
@@ -1319,6 +1501,14 @@ func buildInput(name string, tbl *ast.Table) (*models.InputConfig, error) {
 		}
 	}
 
+	if node, ok := tbl.Fields["route"]; ok {
+		if kv, ok := node.(*ast.KeyValue); ok {
+			if str, ok := kv.Value.(*ast.String); ok {
+				cp.Route = str.Value
+			}
+		}
+	}
+
 	cp.Tags = make(map[string]string)
 	if node, ok := tbl.Fields["tags"]; ok {
 		if subtbl, ok := node.(*ast.Table); ok {
@@ -1332,12 +1522,16 @@ func buildInput(name string, tbl *ast.Table) (*models.InputConfig, error) {
 	delete(tbl.Fields, "name_suffix")
 	delete(tbl.Fields, "name_override")
 	delete(tbl.Fields, "interval")
+	delete(tbl.Fields, "route")
 	delete(tbl.Fields, "tags")
 	var err error
 	cp.Filter, err = buildFilter(tbl)
 	if err != nil {
 		return cp, err
 	}
+	if err := checkNoRoutes(cp.Filter); err != nil {
+		return cp, err
+	}
 	return cp, nil
 }
 
@@ -1775,6 +1969,14 @@ func getParserConfig(name string, tbl *ast.Table) (*parsers.Config, error) {
 // a serializers.Serializer object, and creates it, which can then be added onto
 // an Output object.
 func buildSerializer(name string, tbl *ast.Table) (serializers.Serializer, error) {
+	config, err := getSerializerConfig(name, tbl)
+	if err != nil {
+		return nil, err
+	}
+	return serializers.NewSerializer(config)
+}
+
+func getSerializerConfig(name string, tbl *ast.Table) (*serializers.Config, error) {
 	c := &serializers.Config{TimestampUnits: time.Duration(1 * time.Second)}
 
 	if node, ok := tbl.Fields["data_format"]; ok {
@@ -1916,7 +2118,7 @@ func buildSerializer(name string, tbl *ast.Table) (serializers.Serializer, error
 	delete(tbl.Fields, "splunkmetric_hec_routing")
 	delete(tbl.Fields, "wavefront_source_override")
 	delete(tbl.Fields, "wavefront_use_strict")
-	return serializers.NewSerializer(c)
+	return c, nil
 }
 
 // buildOutput parses output specific items from the ast.Table,
@@ -1979,9 +2181,20 @@ func buildOutput(name string, tbl *ast.Table) (*models.OutputConfig, error) {
 		}
 	}
 
+	if node, ok := tbl.Fields["metric_buffer_limit_bytes"]; ok {
+		if kv, ok := node.(*ast.KeyValue); ok {
+			var size internal.Size
+			if err := size.UnmarshalTOML([]byte(kv.Value.Source())); err != nil {
+				return nil, err
+			}
+			oc.MetricBufferLimitBytes = size.Size
+		}
+	}
+
 	delete(tbl.Fields, "flush_interval")
 	delete(tbl.Fields, "metric_buffer_limit")
 	delete(tbl.Fields, "metric_batch_size")
+	delete(tbl.Fields, "metric_buffer_limit_bytes")
 
 	return oc, nil
 }
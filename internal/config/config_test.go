@@ -7,6 +7,7 @@ import (
 
 	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/internal/models"
+	_ "github.com/influxdata/telegraf/plugins/aggregators/minmax"
 	"github.com/influxdata/telegraf/plugins/inputs"
 	"github.com/influxdata/telegraf/plugins/inputs/exec"
 	"github.com/influxdata/telegraf/plugins/inputs/http_listener_v2"
@@ -14,6 +15,7 @@ import (
 	"github.com/influxdata/telegraf/plugins/inputs/procstat"
 	httpOut "github.com/influxdata/telegraf/plugins/outputs/http"
 	"github.com/influxdata/telegraf/plugins/parsers"
+	_ "github.com/influxdata/telegraf/plugins/processors/printer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -251,3 +253,30 @@ func TestConfig_BadOrdering(t *testing.T) {
 	require.Error(t, err, "bad ordering")
 	assert.Equal(t, "Error parsing ./testdata/non_slice_slice.toml, line 4: cannot unmarshal TOML array into string (need slice)", err.Error())
 }
+
+func TestConfig_AggProcessorsWithoutAggregators(t *testing.T) {
+	c := NewConfig()
+	err := c.LoadConfig("./testdata/processors.toml")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(c.Processors))
+	require.Equal(t, 0, len(c.AggProcessors))
+}
+
+func TestConfig_AggProcessorsWithAggregators(t *testing.T) {
+	// Aggregators are loaded from another file after the processors.
+	c := NewConfig()
+	err := c.LoadConfig("./testdata/processors.toml")
+	require.NoError(t, err)
+	err = c.LoadConfig("./testdata/aggregators.toml")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(c.Processors))
+	require.Equal(t, 1, len(c.AggProcessors))
+	require.True(t, c.Processors[0] != c.AggProcessors[0])
+}
+
+func TestConfig_RoutesOnlyOnOutputs(t *testing.T) {
+	c := NewConfig()
+	err := c.LoadConfig("./testdata/processor_routes.toml")
+	require.Error(t, err)
+	assert.Equal(t, "Error parsing ./testdata/processor_routes.toml, routes can only be set on outputs, use route to assign metrics to a route", err.Error())
+}
@@ -10,31 +10,47 @@ import (
 var (
 	AgentMetricsWritten = selfstat.Register("agent", "metrics_written", map[string]string{})
 	AgentMetricsDropped = selfstat.Register("agent", "metrics_dropped", map[string]string{})
+	AgentBufferBytes    = selfstat.Register("agent", "buffer_bytes", map[string]string{})
+)
+
+// Estimated memory overhead of a metric and of each of its tags and fields.
+const (
+	metricOverhead = 96
+	tagOverhead    = 40
+	fieldOverhead  = 48
 )
 
 // Buffer stores metrics in a circular buffer.
 type Buffer struct {
 	sync.Mutex
 	buf   []telegraf.Metric
-	first int // index of the first/oldest metric
-	last  int // one after the index of the last/newest metric
-	size  int // number of metrics currently in the buffer
-	cap   int // the capacity of the buffer
+	sizes []int64 // estimated size in bytes of each metric in buf
+	first int     // index of the first/oldest metric
+	last  int     // one after the index of the last/newest metric
+	size  int     // number of metrics currently in the buffer
+	cap   int     // the capacity of the buffer
+
+	batchFirst int     // index of the first metric in the batch
+	batchSize  int     // number of metrics currently in the batch
+	batchSizes []int64 // estimated size in bytes of each metric in the batch
 
-	batchFirst int // index of the first metric in the batch
-	batchSize  int // number of metrics currently in the batch
+	bytes      int64 // estimated size in bytes of all metrics including the batch
+	byteLimit  int64 // the maximum bytes of the buffer, 0 if unlimited
+	totalLimit int64 // the maximum bytes of all buffers, 0 if unlimited
 
 	MetricsAdded   selfstat.Stat
 	MetricsWritten selfstat.Stat
 	MetricsDropped selfstat.Stat
 	BufferSize     selfstat.Stat
 	BufferLimit    selfstat.Stat
+	BufferBytes    selfstat.Stat
 }
 
 // NewBuffer returns a new empty Buffer with the given capacity.
 func NewBuffer(name string, capacity int) *Buffer {
 	b := &Buffer{
 		buf:   make([]telegraf.Metric, capacity),
+		sizes: make([]int64, capacity),
 		first: 0,
 		last:  0,
 		size:  0,
@@ -65,12 +81,38 @@ func NewBuffer(name string, capacity int) *Buffer {
 			"buffer_limit",
 			map[string]string{"output": name},
 		),
+		BufferBytes: selfstat.Register(
+			"write",
+			"buffer_bytes",
+			map[string]string{"output": name},
+		),
 	}
 	b.BufferSize.Set(int64(0))
 	b.BufferLimit.Set(int64(capacity))
+	b.BufferBytes.Set(int64(0))
 	return b
 }
 
+// SetByteLimit sets the maximum estimated size in bytes of the metrics in
+// this buffer, and of the metrics in all buffers combined.  When a limit is
+// exceeded the oldest metrics in this buffer are dropped.  A limit of 0 is
+// unlimited.
+func (b *Buffer) SetByteLimit(limit, totalLimit int64) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.byteLimit = limit
+	b.totalLimit = totalLimit
+}
+
+// Bytes returns the estimated size in bytes of the metrics in the buffer.
+func (b *Buffer) Bytes() int64 {
+	b.Lock()
+	defer b.Unlock()
+
+	return b.bytes
+}
+
 // Len returns the number of metrics currently in the buffer.
 func (b *Buffer) Len() int {
 	b.Lock()
@@ -87,6 +129,11 @@ func (b *Buffer) metricAdded() {
 	b.MetricsAdded.Incr(1)
 }
 
+func (b *Buffer) addBytes(size int64) {
+	b.bytes += size
+	AgentBufferBytes.Incr(size)
+}
+
 func (b *Buffer) metricWritten(metric telegraf.Metric) {
 	AgentMetricsWritten.Incr(1)
 	b.MetricsWritten.Incr(1)
@@ -104,6 +151,7 @@ func (b *Buffer) add(m telegraf.Metric) int {
 	// Check if Buffer is full
 	if b.size == b.cap {
 		b.metricDropped(b.buf[b.last])
+		b.addBytes(-b.sizes[b.last])
 		dropped++
 
 		if b.last == b.batchFirst && b.batchSize > 0 {
@@ -114,7 +162,10 @@ func (b *Buffer) add(m telegraf.Metric) int {
 
 	b.metricAdded()
 
+	size := metricSize(m)
 	b.buf[b.last] = m
+	b.sizes[b.last] = size
+	b.addBytes(size)
 	b.last = b.next(b.last)
 
 	if b.size == b.cap {
@@ -122,9 +173,45 @@ func (b *Buffer) add(m telegraf.Metric) int {
 	}
 
 	b.size = min(b.size+1, b.cap)
+
+	dropped += b.dropOverLimit()
 	return dropped
 }
 
+// dropOverLimit drops the oldest metrics until the buffer is within its byte
+// limits and returns the number of dropped metrics.  The newest metric and the
+// metrics in the batch are never dropped.
+func (b *Buffer) dropOverLimit() int {
+	dropped := 0
+	for b.size > 1 && b.overLimit() {
+		b.metricDropped(b.buf[b.first])
+		b.addBytes(-b.sizes[b.first])
+		b.buf[b.first] = nil
+		b.sizes[b.first] = 0
+
+		// The dropped metric is newer than the batch, the position the batch
+		// is restored to moves along with the first metric.
+		if b.first == b.batchFirst && b.batchSize > 0 {
+			b.batchFirst = b.next(b.batchFirst)
+		}
+
+		b.first = b.next(b.first)
+		b.size--
+		dropped++
+	}
+	return dropped
+}
+
+func (b *Buffer) overLimit() bool {
+	if b.byteLimit > 0 && b.bytes > b.byteLimit {
+		return true
+	}
+	if b.totalLimit > 0 && AgentBufferBytes.Get() > b.totalLimit {
+		return true
+	}
+	return false
+}
+
 // Add adds metrics to the buffer and returns number of dropped metrics.
 func (b *Buffer) Add(metrics ...telegraf.Metric) int {
 	b.Lock()
@@ -138,6 +225,7 @@ func (b *Buffer) Add(metrics ...telegraf.Metric) int {
 	}
 
 	b.BufferSize.Set(int64(b.length()))
+	b.BufferBytes.Set(b.bytes)
 	return dropped
 }
 
@@ -157,11 +245,14 @@ func (b *Buffer) Batch(batchSize int) []telegraf.Metric {
 	b.batchFirst = b.cap + b.last - outLen
 	b.batchFirst %= b.cap
 	b.batchSize = outLen
+	b.batchSizes = make([]int64, outLen)
 
 	batchIndex := b.batchFirst
 	for i := range out {
 		out[len(out)-1-i] = b.buf[batchIndex]
+		b.batchSizes[len(out)-1-i] = b.sizes[batchIndex]
 		b.buf[batchIndex] = nil
+		b.sizes[batchIndex] = 0
 		batchIndex = b.next(batchIndex)
 	}
 
@@ -175,12 +266,14 @@ func (b *Buffer) Accept(batch []telegraf.Metric) {
 	b.Lock()
 	defer b.Unlock()
 
-	for _, m := range batch {
+	for i, m := range batch {
 		b.metricWritten(m)
+		b.addBytes(-b.batchMetricSize(i))
 	}
 
 	b.resetBatch()
 	b.BufferSize.Set(int64(b.length()))
+	b.BufferBytes.Set(b.bytes)
 }
 
 // Reject returns the batch, acquired from Batch(), to the buffer and marks it
@@ -209,11 +302,14 @@ func (b *Buffer) Reject(batch []telegraf.Metric) {
 
 		if b.buf[re] != nil {
 			b.metricDropped(b.buf[re])
+			b.addBytes(-b.sizes[re])
 			b.first = b.next(b.first)
 		}
 
 		b.buf[re] = b.buf[rp]
+		b.sizes[re] = b.sizes[rp]
 		b.buf[rp] = nil
+		b.sizes[rp] = 0
 	}
 
 	// Copy metrics from the batch back into the buffer; recall that the
@@ -222,14 +318,17 @@ func (b *Buffer) Reject(batch []telegraf.Metric) {
 		if i < restore {
 			re = b.prev(re)
 			b.buf[re] = batch[i]
+			b.sizes[re] = b.batchMetricSize(i)
 			b.size = min(b.size+1, b.cap)
 		} else {
 			b.metricDropped(batch[i])
+			b.addBytes(-b.batchMetricSize(i))
 		}
 	}
 
 	b.resetBatch()
 	b.BufferSize.Set(int64(b.length()))
+	b.BufferBytes.Set(b.bytes)
 }
 
 // dist returns the distance between two indexes.  Because this data structure
@@ -268,9 +367,34 @@ func (b *Buffer) prev(index int) int {
 	return index
 }
 
+// batchMetricSize returns the size of the metric at index i of the batch, or
+// 0 if the batch has already been accepted or rejected.
+func (b *Buffer) batchMetricSize(i int) int64 {
+	if i < len(b.batchSizes) {
+		return b.batchSizes[i]
+	}
+	return 0
+}
+
 func (b *Buffer) resetBatch() {
 	b.batchFirst = 0
 	b.batchSize = 0
+	b.batchSizes = nil
+}
+
+// metricSize returns an estimate of the memory used by the metric in bytes.
+func metricSize(m telegraf.Metric) int64 {
+	size := int64(metricOverhead + len(m.Name()))
+	for _, tag := range m.TagList() {
+		size += int64(tagOverhead + len(tag.Key) + len(tag.Value))
+	}
+	for _, field := range m.FieldList() {
+		size += int64(fieldOverhead + len(field.Key))
+		if v, ok := field.Value.(string); ok {
+			size += int64(len(v))
+		}
+	}
+	return size
 }
 
 func min(a, b int) int {
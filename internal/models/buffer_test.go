@@ -726,3 +726,101 @@ func TestBuffer_RejectEmptyBatch(t *testing.T) {
 		require.NotNil(t, m)
 	}
 }
+
+func TestBuffer_ByteLimitDropsOldest(t *testing.T) {
+	size := metricSize(Metric())
+	b := setup(NewBuffer("test", 5))
+	b.SetByteLimit(3*size, 0)
+
+	dropped := b.Add(MetricTime(1), MetricTime(2), MetricTime(3), MetricTime(4), MetricTime(5))
+	require.Equal(t, 2, dropped)
+	require.Equal(t, 3, b.Len())
+	require.Equal(t, 3*size, b.Bytes())
+	require.Equal(t, int64(2), b.MetricsDropped.Get())
+
+	batch := b.Batch(5)
+	testutil.RequireMetricsEqual(t,
+		[]telegraf.Metric{
+			MetricTime(5),
+			MetricTime(4),
+			MetricTime(3),
+		}, batch)
+}
+
+func TestBuffer_ByteLimitAcceptAndReject(t *testing.T) {
+	size := metricSize(Metric())
+	b := setup(NewBuffer("test", 5))
+	b.SetByteLimit(3*size, 0)
+
+	b.Add(MetricTime(1), MetricTime(2))
+	batch := b.Batch(2)
+	require.Equal(t, 2*size, b.Bytes())
+
+	b.Reject(batch)
+	require.Equal(t, 2*size, b.Bytes())
+
+	// The metrics in the batch are never dropped by the byte limit.
+	batch = b.Batch(2)
+	b.Add(MetricTime(3), MetricTime(4))
+	require.Equal(t, 3*size, b.Bytes())
+	require.Equal(t, int64(1), b.MetricsDropped.Get())
+
+	b.Accept(batch)
+	require.Equal(t, size, b.Bytes())
+}
+
+func TestBuffer_ByteLimitKeepsBatch(t *testing.T) {
+	size := metricSize(Metric())
+	b := setup(NewBuffer("test", 5))
+	b.SetByteLimit(3*size, 0)
+
+	b.Add(MetricTime(1), MetricTime(2))
+	batch := b.Batch(2)
+	dropped := b.Add(MetricTime(3), MetricTime(4))
+	require.Equal(t, 1, dropped)
+	require.Equal(t, 3, b.Len())
+	require.Equal(t, int64(3), b.BufferSize.Get())
+	require.Equal(t, 3*size, b.Bytes())
+
+	b.Reject(batch)
+	require.Equal(t, 3, b.Len())
+	require.Equal(t, 3*size, b.Bytes())
+
+	batch = b.Batch(5)
+	testutil.RequireMetricsEqual(t,
+		[]telegraf.Metric{
+			MetricTime(4),
+			MetricTime(2),
+			MetricTime(1),
+		}, batch)
+}
+
+func TestBuffer_TotalByteLimit(t *testing.T) {
+	size := metricSize(Metric())
+	AgentBufferBytes.Set(0)
+	defer AgentBufferBytes.Set(0)
+
+	b1 := setup(NewBuffer("test1", 5))
+	b1.SetByteLimit(0, 3*size)
+	b2 := setup(NewBuffer("test2", 5))
+	b2.SetByteLimit(0, 3*size)
+
+	b1.Add(MetricTime(1), MetricTime(2))
+	dropped := b2.Add(MetricTime(3), MetricTime(4))
+	require.Equal(t, 1, dropped)
+	require.Equal(t, 2, b1.Len())
+	require.Equal(t, 1, b2.Len())
+	require.Equal(t, 3*size, AgentBufferBytes.Get())
+}
+
+func TestBuffer_MetricSize(t *testing.T) {
+	m, err := metric.New("cpu",
+		map[string]string{"host": "localhost"},
+		map[string]interface{}{"value": 42.0, "state": "running"},
+		time.Unix(0, 0),
+	)
+	require.NoError(t, err)
+	require.Equal(t,
+		int64(metricOverhead+3+tagOverhead+4+9+2*fieldOverhead+5+5+7),
+		metricSize(m))
+}
@@ -7,6 +7,14 @@ import (
 	"github.com/influxdata/telegraf/filter"
 )
 
+const (
+	// RouteTag is the tag holding the name of the route a metric is on.
+	RouteTag = "_route"
+
+	// DefaultRoute is the route of metrics without a RouteTag.
+	DefaultRoute = "default"
+)
+
 // TagFilter is the name of a tag, and the values on which to filter
 type TagFilter struct {
 	Name   string
@@ -34,11 +42,21 @@ type Filter struct {
 	TagInclude []string
 	tagInclude filter.Filter
 
+	Routes []string
+	routes filter.Filter
+
 	isActive bool
 }
 
 // Compile all Filter lists into filter.Filter objects.
 func (f *Filter) Compile() error {
+	var err error
+	// Routes are selected separately from the other filters.
+	f.routes, err = filter.Compile(f.Routes)
+	if err != nil {
+		return fmt.Errorf("Error compiling 'routes', %s", err)
+	}
+
 	if len(f.NameDrop) == 0 &&
 		len(f.NamePass) == 0 &&
 		len(f.FieldDrop) == 0 &&
@@ -51,7 +69,6 @@ func (f *Filter) Compile() error {
 	}
 
 	f.isActive = true
-	var err error
 	f.nameDrop, err = filter.Compile(f.NameDrop)
 	if err != nil {
 		return fmt.Errorf("Error compiling 'namedrop', %s", err)
@@ -112,6 +129,39 @@ func (f *Filter) Select(metric telegraf.Metric) bool {
 	return true
 }
 
+// rejectReason returns the filters that reject the metric, or an empty string
+// if the metric is selected.
+func (f *Filter) rejectReason(metric telegraf.Metric) string {
+	if !f.isActive {
+		return ""
+	}
+
+	if !f.shouldNamePass(metric.Name()) {
+		return "namepass/namedrop"
+	}
+
+	if !f.shouldTagsPass(metric.TagList()) {
+		return "tagpass/tagdrop"
+	}
+
+	return ""
+}
+
+// SelectRoute returns true if the metric is on one of the routes.  Metrics
+// without a RouteTag are on the DefaultRoute, which is the only route selected
+// when no routes are set.  The metric is not modified.
+func (f *Filter) SelectRoute(metric telegraf.Metric) bool {
+	route, ok := metric.GetTag(RouteTag)
+	if !ok {
+		route = DefaultRoute
+	}
+
+	if f.routes == nil {
+		return route == DefaultRoute
+	}
+	return f.routes.Match(route)
+}
+
 // Modify removes any tags and fields from the metric according to the
 // fieldpass/fielddrop and taginclude/tagexclude filters.
 func (f *Filter) Modify(metric telegraf.Metric) {
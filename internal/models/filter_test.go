@@ -523,3 +523,42 @@ func BenchmarkFilter(b *testing.B) {
 		})
 	}
 }
+
+func TestFilter_SelectRoute(t *testing.T) {
+	unrouted := testutil.MustMetric("m",
+		map[string]string{},
+		map[string]interface{}{"value": int64(1)},
+		time.Now())
+	billing := testutil.MustMetric("m",
+		map[string]string{RouteTag: "billing"},
+		map[string]interface{}{"value": int64(1)},
+		time.Now())
+	backup := testutil.MustMetric("m",
+		map[string]string{RouteTag: "backup"},
+		map[string]interface{}{"value": int64(1)},
+		time.Now())
+
+	f := Filter{}
+	require.NoError(t, f.Compile())
+	require.False(t, f.IsActive())
+	require.True(t, f.SelectRoute(unrouted))
+	require.False(t, f.SelectRoute(billing))
+	require.False(t, f.SelectRoute(backup))
+
+	f = Filter{
+		Routes: []string{"billing"},
+	}
+	require.NoError(t, f.Compile())
+	require.False(t, f.IsActive())
+	require.False(t, f.SelectRoute(unrouted))
+	require.True(t, f.SelectRoute(billing))
+	require.False(t, f.SelectRoute(backup))
+
+	f = Filter{
+		Routes: []string{DefaultRoute, "b*"},
+	}
+	require.NoError(t, f.Compile())
+	require.True(t, f.SelectRoute(unrouted))
+	require.True(t, f.SelectRoute(billing))
+	require.True(t, f.SelectRoute(backup))
+}
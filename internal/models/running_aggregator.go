@@ -64,6 +64,7 @@ type AggregatorConfig struct {
 	MeasurementPrefix string
 	MeasurementSuffix string
 	Tags              map[string]string
+	Route             string
 	Filter            Filter
 }
 
@@ -96,6 +97,10 @@ func (r *RunningAggregator) MakeMetric(metric telegraf.Metric) telegraf.Metric {
 
 	if m != nil {
 		m.SetAggregate(true)
+		if r.Config.Route != "" {
+			m.AddTag(RouteTag, r.Config.Route)
+		}
+		Trace(r.Name(), m, "pushed")
 	}
 
 	r.MetricsPushed.Incr(1)
@@ -107,9 +112,14 @@ func (r *RunningAggregator) MakeMetric(metric telegraf.Metric) telegraf.Metric {
 // should be dropped.
 func (r *RunningAggregator) Add(m telegraf.Metric) bool {
 	if ok := r.Config.Filter.Select(m); !ok {
+		traceRejected(r.Name(), &r.Config.Filter, m, "not aggregated")
 		return false
 	}
 
+	if r.Config.DropOriginal {
+		Trace(r.Name(), m, "original dropped due to drop_original")
+	}
+
 	// Make a copy of the metric but don't retain tracking.  We do not fail a
 	// delivery due to the aggregation not being sent because we can't create
 	// aggregations of historical data.  Additionally, waiting for the
@@ -118,6 +128,7 @@ func (r *RunningAggregator) Add(m telegraf.Metric) bool {
 
 	r.Config.Filter.Modify(m)
 	if len(m.FieldList()) == 0 {
+		Trace(r.Name(), m, "not aggregated, no fields remain after filtering")
 		r.MetricsFiltered.Incr(1)
 		return r.Config.DropOriginal
 	}
@@ -128,10 +139,12 @@ func (r *RunningAggregator) Add(m telegraf.Metric) bool {
 	if m.Time().Before(r.periodStart) || m.Time().After(r.periodEnd.Add(r.Config.Delay)) {
 		log.Printf("D! [%s] metric is outside aggregation window; discarding. %s: m: %s e: %s",
 			r.Name(), m.Time(), r.periodStart, r.periodEnd)
+		Trace(r.Name(), m, "not aggregated, outside of aggregation window")
 		r.MetricsDropped.Incr(1)
 		return r.Config.DropOriginal
 	}
 
+	Trace(r.Name(), m, "aggregated")
 	r.Aggregator.Add(m)
 	return r.Config.DropOriginal
 }
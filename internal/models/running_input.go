@@ -45,6 +45,7 @@ type InputConfig struct {
 	MeasurementPrefix string
 	MeasurementSuffix string
 	Tags              map[string]string
+	Route             string
 	Filter            Filter
 }
 
@@ -58,6 +59,7 @@ func (r *RunningInput) metricFiltered(metric telegraf.Metric) {
 
 func (r *RunningInput) MakeMetric(metric telegraf.Metric) telegraf.Metric {
 	if ok := r.Config.Filter.Select(metric); !ok {
+		traceRejected(r.Name(), &r.Config.Filter, metric, "dropped")
 		r.metricFiltered(metric)
 		return nil
 	}
@@ -72,10 +74,17 @@ func (r *RunningInput) MakeMetric(metric telegraf.Metric) telegraf.Metric {
 
 	r.Config.Filter.Modify(metric)
 	if len(metric.FieldList()) == 0 {
+		Trace(r.Name(), metric, "dropped, no fields remain after filtering")
 		r.metricFiltered(metric)
 		return nil
 	}
 
+	if r.Config.Route != "" {
+		m.AddTag(RouteTag, r.Config.Route)
+	}
+
+	Trace(r.Name(), m, "gathered")
+
 	r.MetricsGathered.Incr(1)
 	GlobalMetricsGathered.Incr(1)
 	return m
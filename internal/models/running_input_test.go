@@ -256,6 +256,39 @@ func TestMakeMetricNameSuffix(t *testing.T) {
 	require.Equal(t, expected, m)
 }
 
+func TestMakeMetricRoute(t *testing.T) {
+	now := time.Now()
+	ri := NewRunningInput(&testInput{}, &InputConfig{
+		Name:  "TestRunningInput",
+		Route: "billing",
+		Filter: Filter{
+			TagInclude: []string{"foo"},
+		},
+	})
+	require.NoError(t, ri.Config.Filter.Compile())
+
+	m, err := metric.New("RITest",
+		map[string]string{},
+		map[string]interface{}{
+			"value": int64(101),
+		},
+		now,
+		telegraf.Untyped)
+	require.NoError(t, err)
+	m = ri.MakeMetric(m)
+	expected, err := metric.New("RITest",
+		map[string]string{
+			RouteTag: "billing",
+		},
+		map[string]interface{}{
+			"value": 101,
+		},
+		now,
+	)
+	require.NoError(t, err)
+	require.Equal(t, expected, m)
+}
+
 type testInput struct{}
 
 func (t *testInput) Description() string                   { return "" }
@@ -26,6 +26,12 @@ type OutputConfig struct {
 	FlushInterval     time.Duration
 	MetricBufferLimit int
 	MetricBatchSize   int
+
+	// MetricBufferLimitBytes is the maximum estimated size of the buffered
+	// metrics of this output, MetricBufferTotalLimitBytes is the maximum for
+	// all outputs combined.  A limit of 0 is unlimited.
+	MetricBufferLimitBytes      int64
+	MetricBufferTotalLimitBytes int64
 }
 
 // RunningOutput contains the output configuration
@@ -88,6 +94,7 @@ func NewRunningOutput(
 			map[string]string{"output": name},
 		),
 	}
+	ro.buffer.SetByteLimit(conf.MetricBufferLimitBytes, conf.MetricBufferTotalLimitBytes)
 
 	return ro
 }
@@ -102,16 +109,23 @@ func (ro *RunningOutput) metricFiltered(metric telegraf.Metric) {
 // Takes ownership of metric
 func (ro *RunningOutput) AddMetric(metric telegraf.Metric) {
 	if ok := ro.Config.Filter.Select(metric); !ok {
+		traceRejected("outputs."+ro.Name, &ro.Config.Filter, metric, "not written")
 		ro.metricFiltered(metric)
 		return
 	}
 
 	ro.Config.Filter.Modify(metric)
 	if len(metric.FieldList()) == 0 {
+		Trace("outputs."+ro.Name, metric, "not written, no fields remain after filtering")
 		ro.metricFiltered(metric)
 		return
 	}
 
+	Trace("outputs."+ro.Name, metric, "added to output")
+
+	// The route is only used within Telegraf and is never written.
+	metric.RemoveTag(RouteTag)
+
 	if output, ok := ro.Output.(telegraf.AggregatingOutput); ok {
 		ro.aggMutex.Lock()
 		output.Add(metric)
@@ -216,6 +216,28 @@ func TestRunningOutput_TagIncludeMatch(t *testing.T) {
 	assert.Len(t, m.Metrics()[0].Tags(), 1)
 }
 
+// Test that the route tag is not written
+func TestRunningOutput_RemoveRouteTag(t *testing.T) {
+	conf := &OutputConfig{
+		Filter: Filter{
+			Routes: []string{"billing"},
+		},
+	}
+	assert.NoError(t, conf.Filter.Compile())
+
+	m := &mockOutput{}
+	ro := NewRunningOutput("test", m, conf, 1000, 10000)
+
+	metric := testutil.TestMetric(101, "metric1")
+	metric.AddTag(RouteTag, "billing")
+	ro.AddMetric(metric)
+
+	err := ro.Write()
+	assert.NoError(t, err)
+	assert.Len(t, m.Metrics(), 1)
+	assert.False(t, m.Metrics()[0].HasTag(RouteTag))
+}
+
 // Test that we can write metrics with simple default setup.
 func TestRunningOutputDefault(t *testing.T) {
 	conf := &OutputConfig{
@@ -7,10 +7,8 @@ import (
 )
 
 type RunningProcessor struct {
-	Name string
-
 	sync.Mutex
-	Processor telegraf.Processor
+	Processor telegraf.StreamingProcessor
 	Config    *ProcessorConfig
 }
 
@@ -24,46 +22,73 @@ func (rp RunningProcessors) Less(i, j int) bool { return rp[i].Config.Order < rp
 type ProcessorConfig struct {
 	Name   string
 	Order  int64
+	Route  string
 	Filter Filter
 }
 
+func NewRunningProcessor(
+	processor telegraf.StreamingProcessor,
+	config *ProcessorConfig,
+) *RunningProcessor {
+	return &RunningProcessor{
+		Processor: processor,
+		Config:    config,
+	}
+}
+
+func (rp *RunningProcessor) Name() string {
+	return "processors." + rp.Config.Name
+}
+
 func (rp *RunningProcessor) metricFiltered(metric telegraf.Metric) {
 	metric.Drop()
 }
 
-func containsMetric(item telegraf.Metric, metrics []telegraf.Metric) bool {
-	for _, m := range metrics {
-		if item == m {
-			return true
-		}
-	}
-	return false
+// MakeMetric returns the metric unmodified, processors do not apply any
+// per plugin modifications to the metrics they emit.
+func (rp *RunningProcessor) MakeMetric(metric telegraf.Metric) telegraf.Metric {
+	Trace(rp.Name(), metric, "emitted")
+	return metric
+}
+
+// Start starts the processor, the Accumulator is used to pass metrics
+// downstream.
+func (rp *RunningProcessor) Start(acc telegraf.Accumulator) error {
+	return rp.Processor.Start(acc)
 }
 
-func (rp *RunningProcessor) Apply(in ...telegraf.Metric) []telegraf.Metric {
+// Add sends the metric through the processor if it is selected by the
+// filter, otherwise the metric is added to the Accumulator unmodified.
+func (rp *RunningProcessor) Add(metric telegraf.Metric, acc telegraf.Accumulator) error {
+	// In processors when a filter selects a metric it is sent through the
+	// processor.  Otherwise the metric continues downstream unmodified.
+	if ok := rp.Config.Filter.Select(metric); !ok {
+		traceRejected(rp.Name(), &rp.Config.Filter, metric, "passed unmodified")
+		acc.AddMetric(metric)
+		return nil
+	}
+
+	rp.Config.Filter.Modify(metric)
+	if len(metric.FieldList()) == 0 {
+		Trace(rp.Name(), metric, "dropped, no fields remain after filtering")
+		rp.metricFiltered(metric)
+		return nil
+	}
+
+	// Only metrics selected by the processor are assigned to its route.
+	if rp.Config.Route != "" {
+		metric.AddTag(RouteTag, rp.Config.Route)
+	}
+
+	Trace(rp.Name(), metric, "processing")
+
 	rp.Lock()
 	defer rp.Unlock()
 
-	ret := []telegraf.Metric{}
-
-	for _, metric := range in {
-		// In processors when a filter selects a metric it is sent through the
-		// processor.  Otherwise the metric continues downstream unmodified.
-		if ok := rp.Config.Filter.Select(metric); !ok {
-			ret = append(ret, metric)
-			continue
-		}
-
-		rp.Config.Filter.Modify(metric)
-		if len(metric.FieldList()) == 0 {
-			rp.metricFiltered(metric)
-			continue
-		}
-
-		// This metric should pass through the filter, so call the filter Apply
-		// function and append results to the output slice.
-		ret = append(ret, rp.Processor.Apply(metric)...)
-	}
+	return rp.Processor.Add(metric, acc)
+}
 
-	return ret
+// Stop stops the processor, it should not be called concurrently with Add.
+func (rp *RunningProcessor) Stop() error {
+	return rp.Processor.Stop()
 }
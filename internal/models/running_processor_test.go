@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/processors"
 	"github.com/influxdata/telegraf/testutil"
 
 	"github.com/stretchr/testify/require"
@@ -41,7 +42,7 @@ func TagProcessor(key, value string) *MockProcessor {
 	}
 }
 
-func TestRunningProcessor_Apply(t *testing.T) {
+func TestRunningProcessor_Add(t *testing.T) {
 	type args struct {
 		Processor telegraf.Processor
 		Config    *ProcessorConfig
@@ -152,18 +153,87 @@ func TestRunningProcessor_Apply(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			rp := &RunningProcessor{
-				Processor: tt.args.Processor,
-				Config:    tt.args.Config,
-			}
+			rp := NewRunningProcessor(
+				processors.NewStreamingProcessorFromProcessor(tt.args.Processor),
+				tt.args.Config,
+			)
 			rp.Config.Filter.Compile()
 
-			actual := rp.Apply(tt.input...)
-			require.Equal(t, tt.expected, actual)
+			var acc testutil.Accumulator
+			err := rp.Start(&acc)
+			require.NoError(t, err)
+			for _, m := range tt.input {
+				err := rp.Add(m, &acc)
+				require.NoError(t, err)
+			}
+			err = rp.Stop()
+			require.NoError(t, err)
+
+			testutil.RequireMetricsEqual(t, tt.expected, acc.GetTelegrafMetrics())
 		})
 	}
 }
 
+// BufferProcessor is a StreamingProcessor that holds all metrics until it is
+// stopped.
+type BufferProcessor struct {
+	acc     telegraf.Accumulator
+	metrics []telegraf.Metric
+}
+
+func (p *BufferProcessor) SampleConfig() string {
+	return ""
+}
+
+func (p *BufferProcessor) Description() string {
+	return ""
+}
+
+func (p *BufferProcessor) Start(acc telegraf.Accumulator) error {
+	p.acc = acc
+	return nil
+}
+
+func (p *BufferProcessor) Add(m telegraf.Metric, acc telegraf.Accumulator) error {
+	p.metrics = append(p.metrics, m)
+	return nil
+}
+
+func (p *BufferProcessor) Stop() error {
+	for _, m := range p.metrics {
+		p.acc.AddMetric(m)
+	}
+	return nil
+}
+
+func TestRunningProcessor_Streaming(t *testing.T) {
+	rp := NewRunningProcessor(&BufferProcessor{}, &ProcessorConfig{
+		Filter: Filter{
+			NamePass: []string{"cpu"},
+		},
+	})
+	rp.Config.Filter.Compile()
+
+	var acc testutil.Accumulator
+	err := rp.Start(&acc)
+	require.NoError(t, err)
+
+	cpu := testutil.MustMetric("cpu", map[string]string{},
+		map[string]interface{}{"value": 42.0}, time.Unix(0, 0))
+	mem := testutil.MustMetric("mem", map[string]string{},
+		map[string]interface{}{"value": 42.0}, time.Unix(0, 0))
+
+	require.NoError(t, rp.Add(cpu, &acc))
+	require.NoError(t, rp.Add(mem, &acc))
+
+	// Metrics not selected by the filter bypass the processor.
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{mem}, acc.GetTelegrafMetrics())
+
+	err = rp.Stop()
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{mem, cpu}, acc.GetTelegrafMetrics())
+}
+
 func TestRunningProcessor_Order(t *testing.T) {
 	rp1 := &RunningProcessor{
 		Config: &ProcessorConfig{
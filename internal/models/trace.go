@@ -0,0 +1,54 @@
+package models
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+)
+
+// traceFilter selects the metrics to trace, tracing is disabled when nil.
+var traceFilter *Filter
+
+// SetTraceFilter enables tracing of the metrics selected by the filter as
+// they pass through the plugins.  Tracing is disabled if the filter is nil.
+//
+// The filter is applied to the metric as it is when it reaches each stage of
+// the pipeline, so a metric which is modified may start or stop being traced.
+func SetTraceFilter(f *Filter) {
+	traceFilter = f
+}
+
+// Trace logs the outcome of a stage of the pipeline for the metric if it is
+// selected by the trace filter.
+func Trace(plugin string, metric telegraf.Metric, format string, args ...interface{}) {
+	if traceFilter == nil || !traceFilter.Select(metric) {
+		return
+	}
+
+	log.Printf("I! [trace] [%s] %s: %s",
+		plugin, traceID(metric), fmt.Sprintf(format, args...))
+}
+
+// traceRejected logs the outcome for a metric rejected by the filter, along
+// with the filters that rejected it.
+func traceRejected(plugin string, f *Filter, metric telegraf.Metric, outcome string) {
+	if traceFilter == nil {
+		return
+	}
+	Trace(plugin, metric, "%s, rejected by %s", outcome, f.rejectReason(metric))
+}
+
+// traceID returns the measurement name and tags identifying the metric.
+func traceID(metric telegraf.Metric) string {
+	var b strings.Builder
+	b.WriteString(metric.Name())
+	for _, tag := range metric.TagList() {
+		b.WriteString(",")
+		b.WriteString(tag.Key)
+		b.WriteString("=")
+		b.WriteString(tag.Value)
+	}
+	return b.String()
+}
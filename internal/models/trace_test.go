@@ -0,0 +1,56 @@
+package models
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrace(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	f := &Filter{
+		NamePass: []string{"cpu"},
+	}
+	require.NoError(t, f.Compile())
+	SetTraceFilter(f)
+	defer SetTraceFilter(nil)
+
+	ro := NewRunningOutput("test", &mockOutput{}, &OutputConfig{
+		Filter: Filter{
+			TagDrop: []TagFilter{
+				{
+					Name:   "cpu",
+					Filter: []string{"cpu0"},
+				},
+			},
+		},
+	}, 1000, 10000)
+	require.NoError(t, ro.Config.Filter.Compile())
+
+	ro.AddMetric(testutil.MustMetric("cpu",
+		map[string]string{"cpu": "cpu0"},
+		map[string]interface{}{"value": 42.0},
+		time.Unix(0, 0)))
+	ro.AddMetric(testutil.MustMetric("cpu",
+		map[string]string{"cpu": "cpu1"},
+		map[string]interface{}{"value": 42.0},
+		time.Unix(0, 0)))
+	ro.AddMetric(testutil.MustMetric("mem",
+		map[string]string{},
+		map[string]interface{}{"value": 42.0},
+		time.Unix(0, 0)))
+
+	require.Contains(t, buf.String(),
+		"I! [trace] [outputs.test] cpu,cpu=cpu0: not written, rejected by tagpass/tagdrop\n")
+	require.Contains(t, buf.String(),
+		"I! [trace] [outputs.test] cpu,cpu=cpu1: added to output\n")
+	require.NotContains(t, buf.String(), "mem")
+}
@@ -0,0 +1,257 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// DefaultStopTimeout is how long Stop waits for the process to exit by
+// default.
+const DefaultStopTimeout = 5 * time.Second
+
+// Process is a long-running external program which is restarted each time it
+// terminates until it is stopped.
+type Process struct {
+	// ReadStdoutFn and ReadStderrFn are called with the output of each run
+	// of the process and must read until the end of the output is reached.
+	ReadStdoutFn func(io.Reader)
+	ReadStderrFn func(io.Reader)
+
+	// RestartDelay is the delay before the process is restarted.  The delay
+	// is doubled each time the process terminates in quick succession, up to
+	// MaxRestartDelay.
+	RestartDelay    time.Duration
+	MaxRestartDelay time.Duration
+
+	// StopTimeout is how long to wait for the process to exit after its
+	// input is closed before it is killed.
+	StopTimeout time.Duration
+
+	name string
+	args []string
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	readWg sync.WaitGroup
+
+	// mu protects the currently running process.
+	mu    sync.Mutex
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+}
+
+// New returns a Process running the command, the name is the plugin used when
+// logging.  The process is not started until Start is called.
+func New(name string, command []string) (*Process, error) {
+	if len(command) == 0 {
+		return nil, errors.New("command is required")
+	}
+
+	return &Process{
+		ReadStdoutFn:    drain,
+		ReadStderrFn:    drain,
+		RestartDelay:    10 * time.Second,
+		MaxRestartDelay: 5 * time.Minute,
+		StopTimeout:     DefaultStopTimeout,
+		name:            name,
+		args:            command,
+	}, nil
+}
+
+// Start starts the process, an error is returned if the first run of the
+// process cannot be started.
+func (p *Process) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	if err := p.cmdStart(ctx); err != nil {
+		cancel()
+		return err
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.cmdLoop(ctx)
+	}()
+
+	return nil
+}
+
+// Stop closes the input of the process, and kills it if it has not exited
+// within the StopTimeout.  Stop returns once the output has been read.
+func (p *Process) Stop() {
+	p.cancel()
+
+	p.mu.Lock()
+	if p.stdin != nil {
+		p.stdin.Close()
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return
+	case <-time.After(p.StopTimeout):
+	}
+
+	p.mu.Lock()
+	if p.cmd != nil && p.cmd.Process != nil {
+		if err := p.cmd.Process.Kill(); err != nil {
+			log.Printf("D! [%s] Error killing process: %v", p.name, err)
+		}
+	}
+	p.mu.Unlock()
+
+	<-done
+}
+
+// Signal sends a signal to the running process.
+func (p *Process) Signal(sig os.Signal) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cmd == nil || p.cmd.Process == nil {
+		return nil
+	}
+	return p.cmd.Process.Signal(sig)
+}
+
+// Write writes to the input of the running process.
+func (p *Process) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	stdin := p.stdin
+	p.mu.Unlock()
+
+	if stdin == nil {
+		return 0, errors.New("process is not running")
+	}
+	return stdin.Write(b)
+}
+
+// cmdStart starts the process and begins reading its output.
+func (p *Process) cmdStart(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Avoid starting a process that would never be stopped.
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	cmd := exec.Command(p.args[0], p.args[1:]...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("error opening stdin pipe: %s", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("error opening stdout pipe: %s", err)
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("error opening stderr pipe: %s", err)
+	}
+
+	log.Printf("D! [%s] Starting process: %s", p.name, p.args)
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("error starting process %s: %s", p.args, err)
+	}
+
+	p.cmd = cmd
+	p.stdin = stdin
+
+	p.readWg.Add(2)
+	go func() {
+		defer p.readWg.Done()
+		p.ReadStdoutFn(stdout)
+	}()
+	go func() {
+		defer p.readWg.Done()
+		p.ReadStderrFn(stderr)
+	}()
+
+	return nil
+}
+
+// cmdWait waits for the process to exit once all output has been read.
+func (p *Process) cmdWait() error {
+	p.mu.Lock()
+	cmd := p.cmd
+	p.mu.Unlock()
+
+	p.readWg.Wait()
+	return cmd.Wait()
+}
+
+// cmdLoop restarts the process each time it exits until the context is done.
+func (p *Process) cmdLoop(ctx context.Context) {
+	delay := p.RestartDelay
+	for {
+		start := time.Now()
+		err := p.cmdWait()
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if err != nil {
+			log.Printf("E! [%s] Process %s terminated: %v", p.name, p.args, err)
+		} else {
+			log.Printf("E! [%s] Process %s terminated", p.name, p.args)
+		}
+
+		// Only back off when the process is repeatedly failing.
+		if time.Since(start) > p.MaxRestartDelay {
+			delay = p.RestartDelay
+		}
+
+		for {
+			log.Printf("I! [%s] Restarting in %s...", p.name, delay)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+
+			delay *= 2
+			if delay > p.MaxRestartDelay {
+				delay = p.MaxRestartDelay
+			}
+
+			err := p.cmdStart(ctx)
+			if err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("E! [%s] %v", p.name, err)
+		}
+	}
+}
+
+func drain(r io.Reader) {
+	io.Copy(ioutil.Discard, r)
+}
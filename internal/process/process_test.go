@@ -0,0 +1,61 @@
+// +build !windows
+
+package process
+
+import (
+	"bufio"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessRestart(t *testing.T) {
+	p, err := New("test", []string{"sh", "-c", "echo started"})
+	require.NoError(t, err)
+	p.RestartDelay = 10 * time.Millisecond
+	p.MaxRestartDelay = 100 * time.Millisecond
+
+	lines := make(chan string, 10)
+	p.ReadStdoutFn = func(r io.Reader) {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}
+
+	require.NoError(t, p.Start())
+	defer p.Stop()
+
+	// The process exits immediately and is started again each time.
+	for i := 0; i < 3; i++ {
+		require.Equal(t, "started", <-lines)
+	}
+}
+
+func TestProcessWrite(t *testing.T) {
+	p, err := New("test", []string{"cat"})
+	require.NoError(t, err)
+
+	lines := make(chan string, 10)
+	p.ReadStdoutFn = func(r io.Reader) {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}
+
+	require.NoError(t, p.Start())
+
+	_, err = p.Write([]byte("hello\n"))
+	require.NoError(t, err)
+	require.Equal(t, "hello", <-lines)
+
+	p.Stop()
+}
+
+func TestProcessNoCommand(t *testing.T) {
+	_, err := New("test", nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,34 @@
+// +build !windows
+
+package execd
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/influxdata/telegraf"
+)
+
+// checkSignal returns an error if the signal is not supported.
+func (e *Execd) checkSignal() error {
+	switch e.Signal {
+	case "SIGHUP", "SIGUSR1", "SIGUSR2", "STDIN", "none":
+		return nil
+	default:
+		return fmt.Errorf("invalid signal: %s", e.Signal)
+	}
+}
+
+func (e *Execd) Gather(acc telegraf.Accumulator) error {
+	switch e.Signal {
+	case "SIGHUP":
+		return e.process.Signal(syscall.SIGHUP)
+	case "SIGUSR1":
+		return e.process.Signal(syscall.SIGUSR1)
+	case "SIGUSR2":
+		return e.process.Signal(syscall.SIGUSR2)
+	case "STDIN":
+		return e.writeStdin()
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+// +build !windows
+
+package execd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/parsers"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestExecd(t *testing.T, script string, signal string) *Execd {
+	parser, err := parsers.NewInfluxParser()
+	require.NoError(t, err)
+
+	e := NewExecd()
+	e.Command = []string{"sh", "-c", script}
+	e.Signal = signal
+	e.RestartDelay = internal.Duration{Duration: 10 * time.Millisecond}
+	e.MaxRestartDelay = internal.Duration{Duration: 100 * time.Millisecond}
+	e.SetParser(parser)
+	return e
+}
+
+func TestExecdSignalNone(t *testing.T) {
+	e := newTestExecd(t, `echo "cpu,host=a usage_idle=42"; exec sleep 10`, "none")
+
+	var acc testutil.Accumulator
+	require.NoError(t, e.Start(&acc))
+	defer e.Stop()
+
+	acc.Wait(1)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"cpu",
+			map[string]string{
+				"host": "a",
+			},
+			map[string]interface{}{
+				"usage_idle": 42.0,
+			},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
+func TestExecdSignalStdin(t *testing.T) {
+	e := newTestExecd(t, `while read line; do echo "cpu usage_idle=42"; done`, "STDIN")
+
+	var acc testutil.Accumulator
+	require.NoError(t, e.Start(&acc))
+	defer e.Stop()
+
+	require.NoError(t, e.Gather(&acc))
+	acc.Wait(1)
+	require.NoError(t, e.Gather(&acc))
+	acc.Wait(2)
+
+	require.Equal(t, 2, len(acc.GetTelegrafMetrics()))
+}
+
+func TestExecdSignalSIGHUP(t *testing.T) {
+	e := newTestExecd(t, `trap 'echo "cpu usage_idle=42"' HUP; while true; do sleep 0.01; done`, "SIGHUP")
+
+	var acc testutil.Accumulator
+	require.NoError(t, e.Start(&acc))
+	defer e.Stop()
+
+	// Give the shell time to install the trap.
+	time.Sleep(200 * time.Millisecond)
+	require.NoError(t, e.Gather(&acc))
+	acc.Wait(1)
+
+	require.Equal(t, 1, len(acc.GetTelegrafMetrics()))
+}
+
+func TestExecdRestart(t *testing.T) {
+	e := newTestExecd(t, `echo "cpu usage_idle=42"`, "none")
+
+	var acc testutil.Accumulator
+	require.NoError(t, e.Start(&acc))
+	defer e.Stop()
+
+	// Each restart of the process produces another metric.
+	acc.Wait(3)
+}
+
+func TestExecdStderr(t *testing.T) {
+	e := newTestExecd(t, `echo "cpu usage_idle=42"; echo "oops" >&2; exec sleep 10`, "none")
+
+	var acc testutil.Accumulator
+	require.NoError(t, e.Start(&acc))
+	defer e.Stop()
+
+	acc.Wait(1)
+	require.Empty(t, acc.Errors)
+}
+
+func TestExecdParseError(t *testing.T) {
+	e := newTestExecd(t, `echo "not line protocol"; echo "cpu usage_idle=42"; exec sleep 10`, "none")
+
+	var acc testutil.Accumulator
+	require.NoError(t, e.Start(&acc))
+	defer e.Stop()
+
+	acc.Wait(1)
+	require.Len(t, acc.Errors, 1)
+}
+
+func TestExecdInvalidSignal(t *testing.T) {
+	e := newTestExecd(t, `exec sleep 10`, "SIGKILL")
+
+	var acc testutil.Accumulator
+	require.Error(t, e.Start(&acc))
+}
+
+func TestExecdNoCommand(t *testing.T) {
+	e := NewExecd()
+
+	var acc testutil.Accumulator
+	require.Error(t, e.Start(&acc))
+}
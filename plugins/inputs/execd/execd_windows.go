@@ -0,0 +1,26 @@
+// +build windows
+
+package execd
+
+import (
+	"fmt"
+
+	"github.com/influxdata/telegraf"
+)
+
+// checkSignal returns an error if the signal is not supported.
+func (e *Execd) checkSignal() error {
+	switch e.Signal {
+	case "STDIN", "none":
+		return nil
+	default:
+		return fmt.Errorf("invalid signal: %s", e.Signal)
+	}
+}
+
+func (e *Execd) Gather(acc telegraf.Accumulator) error {
+	if e.Signal == "STDIN" {
+		return e.writeStdin()
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package exec
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log"
+	"os/exec"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/outputs"
+	"github.com/influxdata/telegraf/plugins/serializers"
+)
+
+const sampleConfig = `
+  ## Command to run for each batch of metrics, the first element is the
+  ## program and the remaining elements are its arguments.  The metrics are
+  ## written to the standard input of the command.
+  command = ["tee", "-a", "/dev/null"]
+
+  ## Timeout for the command to complete.
+  # timeout = "5s"
+
+  ## Data format to output.
+  ## Each data format has its own unique set of configuration options, read
+  ## more about them here:
+  ## https://github.com/influxdata/telegraf/blob/master/docs/DATA_FORMATS_OUTPUT.md
+  # data_format = "influx"
+`
+
+type Exec struct {
+	Command []string          `toml:"command"`
+	Timeout internal.Duration `toml:"timeout"`
+
+	serializer serializers.Serializer
+}
+
+func (e *Exec) SetSerializer(serializer serializers.Serializer) {
+	e.serializer = serializer
+}
+
+func (e *Exec) Connect() error {
+	if len(e.Command) == 0 {
+		return fmt.Errorf("command is required")
+	}
+	return nil
+}
+
+func (e *Exec) Close() error {
+	return nil
+}
+
+func (e *Exec) SampleConfig() string {
+	return sampleConfig
+}
+
+func (e *Exec) Description() string {
+	return "Send metrics to command as input over stdin"
+}
+
+// Write runs the command with the batch of metrics as its input.  The batch
+// is written successfully only if the command exits with a zero status.
+func (e *Exec) Write(metrics []telegraf.Metric) error {
+	b, err := e.serializer.SerializeBatch(metrics)
+	if err != nil {
+		return fmt.Errorf("error serializing metrics: %s", err)
+	}
+
+	if len(b) == 0 {
+		return nil
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(e.Command[0], e.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(b)
+	cmd.Stderr = &stderr
+
+	err = internal.RunTimeout(cmd, e.Timeout.Duration)
+
+	scanner := bufio.NewScanner(&stderr)
+	for scanner.Scan() {
+		log.Printf("E! [outputs.exec] stderr: %q", scanner.Text())
+	}
+
+	if err != nil {
+		return fmt.Errorf("command %s failed: %s", e.Command, err)
+	}
+	return nil
+}
+
+func init() {
+	outputs.Add("exec", func() telegraf.Output {
+		return &Exec{
+			Timeout: internal.Duration{Duration: 5 * time.Second},
+		}
+	})
+}
@@ -0,0 +1,69 @@
+// +build !windows
+
+package exec
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/serializers"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestExec(t *testing.T, command ...string) *Exec {
+	serializer, err := serializers.NewInfluxSerializer()
+	require.NoError(t, err)
+
+	e := &Exec{
+		Command: command,
+		Timeout: internal.Duration{Duration: 5 * time.Second},
+	}
+	e.SetSerializer(serializer)
+	require.NoError(t, e.Connect())
+	return e
+}
+
+func TestExec(t *testing.T) {
+	dir, err := ioutil.TempDir("", "exec")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "out")
+
+	e := newTestExec(t, "sh", "-c", "cat > "+filename)
+
+	require.NoError(t, e.Write(testutil.MockMetrics()))
+
+	b, err := ioutil.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, "test1,tag1=value1 value=1 1257894000000000000\n", string(b))
+}
+
+func TestExecFailure(t *testing.T) {
+	e := newTestExec(t, "sh", "-c", "cat > /dev/null; echo oops >&2; exit 1")
+
+	require.Error(t, e.Write(testutil.MockMetrics()))
+}
+
+func TestExecTimeout(t *testing.T) {
+	e := newTestExec(t, "sleep", "10")
+	e.Timeout = internal.Duration{Duration: 10 * time.Millisecond}
+
+	require.Error(t, e.Write(testutil.MockMetrics()))
+}
+
+func TestExecEmptyBatch(t *testing.T) {
+	e := newTestExec(t, "false")
+
+	require.NoError(t, e.Write([]telegraf.Metric{}))
+}
+
+func TestExecNoCommand(t *testing.T) {
+	e := &Exec{}
+	require.Error(t, e.Connect())
+}
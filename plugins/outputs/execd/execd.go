@@ -0,0 +1,156 @@
+package execd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/internal/process"
+	"github.com/influxdata/telegraf/plugins/outputs"
+	"github.com/influxdata/telegraf/plugins/serializers"
+)
+
+const sampleConfig = `
+  ## Program to run as daemon, the first element is the program and the
+  ## remaining elements are its arguments.  The metrics are written to the
+  ## standard input of the program.
+  command = ["my-telegraf-output", "--some-flag", "value"]
+
+  ## Timeout for the program to accept each batch of metrics.  If the
+  ## timeout is reached the program is killed and restarted.  When Telegraf
+  ## stops, the program is also given this long to exit after its input is
+  ## closed.
+  # timeout = "5s"
+
+  ## Delay before the process is restarted after an unexpected termination.
+  ## The delay is doubled after each consecutive restart up to
+  ## max_restart_delay, and is reset once the process has run for longer
+  ## than max_restart_delay.
+  restart_delay = "10s"
+  max_restart_delay = "5m"
+
+  ## Data format to output.
+  ## Each data format has its own unique set of configuration options, read
+  ## more about them here:
+  ## https://github.com/influxdata/telegraf/blob/master/docs/DATA_FORMATS_OUTPUT.md
+  data_format = "influx"
+`
+
+type Execd struct {
+	Command         []string          `toml:"command"`
+	Timeout         internal.Duration `toml:"timeout"`
+	RestartDelay    internal.Duration `toml:"restart_delay"`
+	MaxRestartDelay internal.Duration `toml:"max_restart_delay"`
+
+	serializer serializers.Serializer
+	process    *process.Process
+}
+
+func NewExecd() *Execd {
+	return &Execd{
+		Timeout:         internal.Duration{Duration: 5 * time.Second},
+		RestartDelay:    internal.Duration{Duration: 10 * time.Second},
+		MaxRestartDelay: internal.Duration{Duration: 5 * time.Minute},
+	}
+}
+
+func (e *Execd) SetSerializer(serializer serializers.Serializer) {
+	e.serializer = serializer
+}
+
+func (e *Execd) Connect() error {
+	var err error
+	e.process, err = process.New("outputs.execd", e.Command)
+	if err != nil {
+		return err
+	}
+	e.process.ReadStdoutFn = e.cmdReadOut
+	e.process.ReadStderrFn = e.cmdReadErr
+	e.process.RestartDelay = e.RestartDelay.Duration
+	e.process.MaxRestartDelay = e.MaxRestartDelay.Duration
+	e.process.StopTimeout = e.Timeout.Duration
+
+	return e.process.Start()
+}
+
+func (e *Execd) Close() error {
+	e.process.Stop()
+	return nil
+}
+
+func (e *Execd) SampleConfig() string {
+	return sampleConfig
+}
+
+func (e *Execd) Description() string {
+	return "Run executable as long-running output plugin"
+}
+
+// Write writes the batch of metrics to the input of the process.
+func (e *Execd) Write(metrics []telegraf.Metric) error {
+	b, err := e.serializer.SerializeBatch(metrics)
+	if err != nil {
+		return fmt.Errorf("error serializing metrics: %s", err)
+	}
+
+	if len(b) == 0 {
+		return nil
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := e.process.Write(b)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("error writing to stdin: %s", err)
+		}
+		return nil
+	case <-time.After(e.Timeout.Duration):
+		// Killing the process unblocks the write, the process is then
+		// restarted as if it had terminated.
+		if err := e.process.Signal(os.Kill); err != nil {
+			log.Printf("E! [outputs.execd] Error killing process: %v", err)
+		}
+		<-done
+		return fmt.Errorf("timeout writing to stdin after %s", e.Timeout.Duration)
+	}
+}
+
+// cmdReadOut forwards each line of the process output to the log.
+func (e *Execd) cmdReadOut(out io.Reader) {
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		log.Printf("I! [outputs.execd] stdout: %q", scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Printf("E! [outputs.execd] Error reading stdout: %v", err)
+	}
+}
+
+// cmdReadErr forwards each line of the process error output to the log.
+func (e *Execd) cmdReadErr(out io.Reader) {
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		log.Printf("E! [outputs.execd] stderr: %q", scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Printf("E! [outputs.execd] Error reading stderr: %v", err)
+	}
+}
+
+func init() {
+	outputs.Add("execd", func() telegraf.Output {
+		return NewExecd()
+	})
+}
@@ -0,0 +1,67 @@
+// +build !windows
+
+package execd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/serializers"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestExecd(t *testing.T, command ...string) *Execd {
+	serializer, err := serializers.NewInfluxSerializer()
+	require.NoError(t, err)
+
+	e := NewExecd()
+	e.Command = command
+	e.RestartDelay = internal.Duration{Duration: time.Hour}
+	e.MaxRestartDelay = internal.Duration{Duration: time.Hour}
+	e.SetSerializer(serializer)
+	return e
+}
+
+func TestExecd(t *testing.T) {
+	dir, err := ioutil.TempDir("", "execd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "out")
+
+	e := newTestExecd(t, "sh", "-c", "exec cat > "+filename)
+	require.NoError(t, e.Connect())
+
+	require.NoError(t, e.Write(testutil.MockMetrics()))
+	require.NoError(t, e.Write(testutil.MockMetrics()))
+	require.NoError(t, e.Close())
+
+	b, err := ioutil.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, strings.Repeat("test1,tag1=value1 value=1 1257894000000000000\n", 2), string(b))
+}
+
+func TestExecdTimeout(t *testing.T) {
+	e := newTestExecd(t, "sleep", "10")
+	e.Timeout = internal.Duration{Duration: 100 * time.Millisecond}
+	require.NoError(t, e.Connect())
+	defer e.Close()
+
+	// Write more than fits in the pipe buffer so the write blocks.
+	metrics := make([]telegraf.Metric, 0, 10000)
+	for i := 0; i < 10000; i++ {
+		metrics = append(metrics, testutil.TestMetric(i))
+	}
+	require.Error(t, e.Write(metrics))
+}
+
+func TestExecdNoCommand(t *testing.T) {
+	e := NewExecd()
+	require.Error(t, e.Connect())
+}
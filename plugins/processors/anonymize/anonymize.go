@@ -0,0 +1,263 @@
+package anonymize
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net"
+	"regexp"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+const sampleConfig = `
+  ## Rules are applied in order, all rules matching a metric are applied.
+  [[processors.anonymize.rule]]
+    ## Measurements the rule applies to, glob patterns are accepted.  All
+    ## measurements if unset.
+    # measurement = ["http_*"]
+
+    ## Tags and fields to anonymize, glob patterns are accepted.
+    tags = ["user_id"]
+    # fields = []
+
+    ## Method used to anonymize the values, one of:
+    ##   "hmac"      : Replace the value by the hex encoded keyed hash of the
+    ##                 value, equal values have equal hashes.
+    ##   "ip_prefix" : Truncate IP addresses to a network prefix, other values
+    ##                 are not modified.
+    ##   "mask"      : Replace the matches of a regular expression.
+    method = "hmac"
+
+    ## Options of the hmac method: the secret key, the hash function, one of
+    ## "sha1", "sha256" or "sha512", and the number of hex characters the hash
+    ## is truncated to, 0 to keep the full hash.
+    key = "${HMAC_KEY}"
+    # hash = "sha256"
+    # length = 0
+
+    ## Options of the ip_prefix method: the length of the network prefix kept
+    ## for IPv4 and IPv6 addresses.
+    # ipv4_prefix = 24
+    # ipv6_prefix = 48
+
+    ## Options of the mask method: the regular expression and its replacement,
+    ## which may refer to subgroups such as ${1}.
+    # pattern = "[0-9]"
+    # replacement = "x"
+`
+
+type Anonymize struct {
+	Rules []*rule `toml:"rule"`
+
+	valid bool
+}
+
+type rule struct {
+	Measurement []string `toml:"measurement"`
+	Tags        []string `toml:"tags"`
+	Fields      []string `toml:"fields"`
+	Method      string   `toml:"method"`
+	Key         string   `toml:"key"`
+	Hash        string   `toml:"hash"`
+	Length      int      `toml:"length"`
+	IPv4Prefix  *int     `toml:"ipv4_prefix"`
+	IPv6Prefix  *int     `toml:"ipv6_prefix"`
+	Pattern     string   `toml:"pattern"`
+	Replacement string   `toml:"replacement"`
+
+	measurementFilter filter.Filter
+	tagFilter         filter.Filter
+	fieldFilter       filter.Filter
+	hash              func() hash.Hash
+	ipv4Mask          net.IPMask
+	ipv6Mask          net.IPMask
+	pattern           *regexp.Regexp
+}
+
+func (a *Anonymize) SampleConfig() string {
+	return sampleConfig
+}
+
+func (a *Anonymize) Description() string {
+	return "Anonymize tag and field values by hashing, truncating or masking them"
+}
+
+func (a *Anonymize) Init() error {
+	a.valid = false
+	for _, r := range a.Rules {
+		if err := r.init(); err != nil {
+			return err
+		}
+	}
+	a.valid = true
+	return nil
+}
+
+func (r *rule) init() error {
+	if len(r.Tags) == 0 && len(r.Fields) == 0 {
+		return errors.New("tags or fields must be set")
+	}
+
+	var err error
+	if r.measurementFilter, err = filter.Compile(r.Measurement); err != nil {
+		return err
+	}
+	if r.tagFilter, err = filter.Compile(r.Tags); err != nil {
+		return err
+	}
+	if r.fieldFilter, err = filter.Compile(r.Fields); err != nil {
+		return err
+	}
+
+	switch r.Method {
+	case "hmac":
+		if r.Key == "" {
+			return errors.New("key is required for the hmac method")
+		}
+		switch r.Hash {
+		case "sha1":
+			r.hash = sha1.New
+		case "sha256", "":
+			r.hash = sha256.New
+		case "sha512":
+			r.hash = sha512.New
+		default:
+			return fmt.Errorf("invalid hash: %s", r.Hash)
+		}
+		if r.Length < 0 {
+			return fmt.Errorf("invalid length: %d", r.Length)
+		}
+	case "ip_prefix":
+		ipv4Prefix, ipv6Prefix := 24, 48
+		if r.IPv4Prefix != nil {
+			ipv4Prefix = *r.IPv4Prefix
+		}
+		if r.IPv6Prefix != nil {
+			ipv6Prefix = *r.IPv6Prefix
+		}
+		if ipv4Prefix < 0 || ipv4Prefix > 32 {
+			return fmt.Errorf("invalid ipv4_prefix: %d", ipv4Prefix)
+		}
+		if ipv6Prefix < 0 || ipv6Prefix > 128 {
+			return fmt.Errorf("invalid ipv6_prefix: %d", ipv6Prefix)
+		}
+		r.ipv4Mask = net.CIDRMask(ipv4Prefix, 32)
+		r.ipv6Mask = net.CIDRMask(ipv6Prefix, 128)
+	case "mask":
+		if r.Pattern == "" {
+			return errors.New("pattern is required for the mask method")
+		}
+		if r.pattern, err = regexp.Compile(r.Pattern); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid method: %s", r.Method)
+	}
+	return nil
+}
+
+func (a *Anonymize) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	// Metrics are never passed on without being anonymized.
+	if !a.valid {
+		for _, m := range in {
+			m.Drop()
+		}
+		return nil
+	}
+
+	for _, m := range in {
+		for _, r := range a.Rules {
+			if r.measurementFilter != nil && !r.measurementFilter.Match(m.Name()) {
+				continue
+			}
+			r.apply(m)
+		}
+	}
+	return in
+}
+
+func (r *rule) apply(m telegraf.Metric) {
+	// The values are collected first, as the metric is modified while
+	// replacing them.
+	if r.tagFilter != nil {
+		tags := make(map[string]string)
+		for _, tag := range m.TagList() {
+			if !r.tagFilter.Match(tag.Key) {
+				continue
+			}
+			if value, ok := r.anonymize(tag.Value); ok {
+				tags[tag.Key] = value
+			}
+		}
+		for key, value := range tags {
+			m.AddTag(key, value)
+		}
+	}
+
+	if r.fieldFilter != nil {
+		fields := make(map[string]string)
+		for _, field := range m.FieldList() {
+			if !r.fieldFilter.Match(field.Key) {
+				continue
+			}
+
+			// Values of other types are hashed using their string
+			// representation, and can only be replaced by a string.
+			value, ok := field.Value.(string)
+			if !ok {
+				if r.Method != "hmac" {
+					continue
+				}
+				value = fmt.Sprint(field.Value)
+			}
+
+			if value, ok := r.anonymize(value); ok {
+				fields[field.Key] = value
+			}
+		}
+		for key, value := range fields {
+			m.AddField(key, value)
+		}
+	}
+}
+
+// anonymize returns the anonymized value, ok is false if the value is not
+// modified.
+func (r *rule) anonymize(value string) (string, bool) {
+	switch r.Method {
+	case "hmac":
+		mac := hmac.New(r.hash, []byte(r.Key))
+		mac.Write([]byte(value))
+		sum := hex.EncodeToString(mac.Sum(nil))
+		if r.Length > 0 && r.Length < len(sum) {
+			sum = sum[:r.Length]
+		}
+		return sum, true
+	case "ip_prefix":
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return "", false
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			return ip4.Mask(r.ipv4Mask).String(), true
+		}
+		return ip.Mask(r.ipv6Mask).String(), true
+	case "mask":
+		return r.pattern.ReplaceAllString(value, r.Replacement), true
+	}
+	return "", false
+}
+
+func init() {
+	processors.Add("anonymize", func() telegraf.Processor {
+		return &Anonymize{}
+	})
+}
@@ -0,0 +1,192 @@
+package anonymize
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/processors"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnonymize(t *testing.T) {
+	var tests = []struct {
+		name     string
+		rules    []*rule
+		input    telegraf.Metric
+		expected telegraf.Metric
+	}{
+		{
+			name: "hmac tag",
+			rules: []*rule{
+				{Tags: []string{"user"}, Method: "hmac", Key: "secret"},
+			},
+			input: testutil.MustMetric("http",
+				map[string]string{"user": "alice", "path": "/"},
+				map[string]interface{}{"value": 42},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("http",
+				map[string]string{
+					"user": "4360c67bc81025114044578d7c4e8e0f02fd0cae99f22d603390e8f9dc9888f8",
+					"path": "/",
+				},
+				map[string]interface{}{"value": 42},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "hmac integer field truncated",
+			rules: []*rule{
+				{Fields: []string{"user_*"}, Method: "hmac", Key: "secret", Hash: "sha1", Length: 12},
+			},
+			input: testutil.MustMetric("http",
+				map[string]string{},
+				map[string]interface{}{"user_id": 42, "value": 42},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("http",
+				map[string]string{},
+				map[string]interface{}{"user_id": "d260a025bdbb", "value": 42},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "ip prefix",
+			rules: []*rule{
+				{Tags: []string{"client_ip", "server_ip", "name"}, Method: "ip_prefix"},
+			},
+			input: testutil.MustMetric("http",
+				map[string]string{"client_ip": "192.0.2.123", "server_ip": "2001:db8:1:2:3::1", "name": "web"},
+				map[string]interface{}{"value": 42},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("http",
+				map[string]string{"client_ip": "192.0.2.0", "server_ip": "2001:db8:1::", "name": "web"},
+				map[string]interface{}{"value": 42},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "ip prefix lengths",
+			rules: []*rule{
+				{Fields: []string{"ip"}, Method: "ip_prefix", IPv4Prefix: intPtr(16)},
+			},
+			input: testutil.MustMetric("http",
+				map[string]string{},
+				map[string]interface{}{"ip": "192.0.2.123"},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("http",
+				map[string]string{},
+				map[string]interface{}{"ip": "192.0.0.0"},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "mask",
+			rules: []*rule{
+				{Fields: []string{"message"}, Method: "mask", Pattern: `(\w+)@[\w.]+`, Replacement: "${1}@***"},
+			},
+			input: testutil.MustMetric("syslog",
+				map[string]string{},
+				map[string]interface{}{"message": "login by alice@example.org", "value": 42},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("syslog",
+				map[string]string{},
+				map[string]interface{}{"message": "login by alice@***", "value": 42},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "measurement filter",
+			rules: []*rule{
+				{Measurement: []string{"nginx*"}, Tags: []string{"user"}, Method: "mask", Pattern: ".+", Replacement: "x"},
+				{Measurement: []string{"http*"}, Tags: []string{"user"}, Method: "mask", Pattern: ".+", Replacement: "y"},
+			},
+			input: testutil.MustMetric("http",
+				map[string]string{"user": "alice"},
+				map[string]interface{}{"value": 42},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("http",
+				map[string]string{"user": "y"},
+				map[string]interface{}{"value": 42},
+				time.Unix(0, 0),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := &Anonymize{Rules: tt.rules}
+			require.NoError(t, plugin.Init())
+			actual := plugin.Apply(tt.input)
+			testutil.RequireMetricsEqual(t, []telegraf.Metric{tt.expected}, actual)
+		})
+	}
+}
+
+func TestInvalidConfiguration(t *testing.T) {
+	var tests = []struct {
+		name string
+		rule *rule
+	}{
+		{
+			name: "no tags or fields",
+			rule: &rule{Method: "hmac", Key: "secret"},
+		},
+		{
+			name: "invalid method",
+			rule: &rule{Tags: []string{"user"}, Method: "encrypt"},
+		},
+		{
+			name: "hmac without key",
+			rule: &rule{Tags: []string{"user"}, Method: "hmac"},
+		},
+		{
+			name: "invalid hash",
+			rule: &rule{Tags: []string{"user"}, Method: "hmac", Key: "secret", Hash: "md5"},
+		},
+		{
+			name: "invalid prefix",
+			rule: &rule{Tags: []string{"ip"}, Method: "ip_prefix", IPv4Prefix: intPtr(33)},
+		},
+		{
+			name: "mask without pattern",
+			rule: &rule{Tags: []string{"user"}, Method: "mask"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := &Anonymize{Rules: []*rule{tt.rule}}
+			require.Error(t, plugin.Init())
+		})
+	}
+}
+
+func TestInvalidConfigurationDropsMetrics(t *testing.T) {
+	creator := processors.Processors["anonymize"]
+	require.NotNil(t, creator)
+
+	processor := creator()
+	plugin := processor.(processors.Unwrapper).Unwrap().(*Anonymize)
+	plugin.Rules = []*rule{{Tags: []string{"user"}, Method: "hmac", Key: ""}}
+
+	acc := &testutil.Accumulator{}
+	require.Error(t, processor.Start(acc))
+
+	m := testutil.MustMetric("access",
+		map[string]string{"user": "alice"},
+		map[string]interface{}{"value": 42},
+		time.Unix(0, 0),
+	)
+	require.Empty(t, plugin.Apply(m))
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,107 @@
+package clone
+
+import (
+	"errors"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+const sampleConfig = `
+  ## All modifications on inputs and aggregators can be overridden:
+  # name_override = "new_name"
+  # name_prefix = "new_name_prefix"
+  # name_suffix = "new_name_suffix"
+
+  ## Tags to be added (all values must be strings)
+  # [processors.clone.tags]
+  #   additional_tag = "tag_value"
+
+  ## To create more than one copy of each metric, define each copy in its own
+  ## table instead of the settings above.  The original is emitted followed
+  ## by the copies in order.
+  # [[processors.clone.copy]]
+  #   name_override = "new_name"
+  #   [processors.clone.copy.tags]
+  #     additional_tag = "tag_value"
+  # [[processors.clone.copy]]
+  #   name_suffix = "_copy"
+`
+
+type Clone struct {
+	NameOverride string            `toml:"name_override"`
+	NamePrefix   string            `toml:"name_prefix"`
+	NameSuffix   string            `toml:"name_suffix"`
+	Tags         map[string]string `toml:"tags"`
+	Copies       []*copyConfig     `toml:"copy"`
+
+	copies []*copyConfig
+}
+
+// copyConfig holds the modifications applied to a copy.
+type copyConfig struct {
+	NameOverride string            `toml:"name_override"`
+	NamePrefix   string            `toml:"name_prefix"`
+	NameSuffix   string            `toml:"name_suffix"`
+	Tags         map[string]string `toml:"tags"`
+}
+
+func (c *Clone) SampleConfig() string {
+	return sampleConfig
+}
+
+func (c *Clone) Description() string {
+	return "Clone metrics and apply modifications to the copies"
+}
+
+func (c *Clone) Init() error {
+	if len(c.Copies) == 0 {
+		c.copies = []*copyConfig{{
+			NameOverride: c.NameOverride,
+			NamePrefix:   c.NamePrefix,
+			NameSuffix:   c.NameSuffix,
+			Tags:         c.Tags,
+		}}
+		return nil
+	}
+
+	if c.NameOverride != "" || c.NamePrefix != "" || c.NameSuffix != "" || len(c.Tags) > 0 {
+		return errors.New("name_override, name_prefix, name_suffix and tags must be set on each copy when copy is used")
+	}
+	c.copies = c.Copies
+	return nil
+}
+
+func (c *Clone) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	out := make([]telegraf.Metric, 0, (len(c.copies)+1)*len(in))
+	for _, original := range in {
+		out = append(out, original)
+		for _, cc := range c.copies {
+			out = append(out, cc.apply(original.Copy()))
+		}
+	}
+	return out
+}
+
+// apply modifies the copy of a metric.
+func (cc *copyConfig) apply(m telegraf.Metric) telegraf.Metric {
+	if cc.NameOverride != "" {
+		m.SetName(cc.NameOverride)
+	}
+	if cc.NamePrefix != "" {
+		m.AddPrefix(cc.NamePrefix)
+	}
+	if cc.NameSuffix != "" {
+		m.AddSuffix(cc.NameSuffix)
+	}
+	for key, value := range cc.Tags {
+		m.AddTag(key, value)
+	}
+	return m
+}
+
+func init() {
+	processors.Add("clone", func() telegraf.Processor {
+		return &Clone{}
+	})
+}
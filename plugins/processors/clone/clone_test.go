@@ -0,0 +1,137 @@
+package clone
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	var tests = []struct {
+		name     string
+		plugin   *Clone
+		expected telegraf.Metric
+	}{
+		{
+			name:   "unmodified",
+			plugin: &Clone{},
+			expected: testutil.MustMetric("cpu",
+				map[string]string{"host": "example.org"},
+				map[string]interface{}{"usage_idle": 42.0},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name:   "name override",
+			plugin: &Clone{NameOverride: "cpu_copy"},
+			expected: testutil.MustMetric("cpu_copy",
+				map[string]string{"host": "example.org"},
+				map[string]interface{}{"usage_idle": 42.0},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name:   "name prefix and suffix",
+			plugin: &Clone{NamePrefix: "dc1_", NameSuffix: "_copy"},
+			expected: testutil.MustMetric("dc1_cpu_copy",
+				map[string]string{"host": "example.org"},
+				map[string]interface{}{"usage_idle": 42.0},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "added tags",
+			plugin: &Clone{
+				Tags: map[string]string{"output": "archive", "host": "replaced"},
+			},
+			expected: testutil.MustMetric("cpu",
+				map[string]string{"host": "replaced", "output": "archive"},
+				map[string]interface{}{"usage_idle": 42.0},
+				time.Unix(0, 0),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := testutil.MustMetric("cpu",
+				map[string]string{"host": "example.org"},
+				map[string]interface{}{"usage_idle": 42.0},
+				time.Unix(0, 0),
+			)
+			original := input.Copy()
+
+			require.NoError(t, tt.plugin.Init())
+			actual := tt.plugin.Apply(input)
+			testutil.RequireMetricsEqual(t, []telegraf.Metric{original, tt.expected}, actual)
+		})
+	}
+}
+
+func TestMultipleCopies(t *testing.T) {
+	plugin := &Clone{
+		Copies: []*copyConfig{
+			{NameOverride: "cpu_archive", Tags: map[string]string{"retention": "long"}},
+			{NameSuffix: "_copy"},
+		},
+	}
+	require.NoError(t, plugin.Init())
+
+	input := testutil.MustMetric("cpu",
+		map[string]string{"host": "example.org"},
+		map[string]interface{}{"usage_idle": 42.0},
+		time.Unix(0, 0),
+	)
+
+	expected := []telegraf.Metric{
+		input.Copy(),
+		testutil.MustMetric("cpu_archive",
+			map[string]string{"host": "example.org", "retention": "long"},
+			map[string]interface{}{"usage_idle": 42.0},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("cpu_copy",
+			map[string]string{"host": "example.org"},
+			map[string]interface{}{"usage_idle": 42.0},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, plugin.Apply(input))
+}
+
+func TestCopyWithTopLevelSettings(t *testing.T) {
+	plugin := &Clone{
+		NameOverride: "cpu_copy",
+		Copies:       []*copyConfig{{NameSuffix: "_copy"}},
+	}
+	require.Error(t, plugin.Init())
+}
+
+func TestTracking(t *testing.T) {
+	var delivered bool
+	input, _ := metric.WithTracking(
+		testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{"usage_idle": 42.0},
+			time.Unix(0, 0),
+		),
+		func(telegraf.DeliveryInfo) {
+			delivered = true
+		},
+	)
+
+	plugin := &Clone{NameOverride: "cpu_copy"}
+	require.NoError(t, plugin.Init())
+	actual := plugin.Apply(input)
+	require.Len(t, actual, 2)
+
+	// The metric is delivered once both the original and the copy are.
+	actual[0].Accept()
+	require.False(t, delivered)
+	actual[1].Accept()
+	require.True(t, delivered)
+}
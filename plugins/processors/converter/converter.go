@@ -5,6 +5,8 @@ import (
 	"log"
 	"math"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/filter"
@@ -12,17 +14,22 @@ import (
 )
 
 var sampleConfig = `
+  ## Tag to set to the measurement name, before any conversions.
+  # measurement_tag = "measurement"
+
   ## Tags to convert
   ##
   ## The table key determines the target type, and the array of key-values
   ## select the keys to convert.  The array may contain globs.
   ##   <target-type> = [<tag-key>...]
   [processors.converter.tags]
+    measurement = []
     string = []
     integer = []
     unsigned = []
     boolean = []
     float = []
+    timestamp = []
 
   ## Fields to convert
   ##
@@ -30,26 +37,61 @@ var sampleConfig = `
   ## select the keys to convert.  The array may contain globs.
   ##   <target-type> = [<field-key>...]
   [processors.converter.fields]
+    measurement = []
     tag = []
     string = []
     integer = []
     unsigned = []
     boolean = []
     float = []
+    timestamp = []
+
+    ## The following options are available in both the tags and the fields
+    ## tables.
+    ##
+    ## Format of the values converted to the metric time, one of "unix",
+    ## "unix_ms", "unix_us", "unix_ns", or a Go "reference time" layout such
+    ## as "2006-01-02T15:04:05Z07:00".
+    # timestamp_format = "unix"
+
+    ## Timezone of the times without a timezone, one of "UTC", "Local" or a
+    ## location name in the IANA Time Zone database.
+    # timezone = "UTC"
+
+    ## Base of strings converted to integer and unsigned.  With the default
+    ## of 0 the base is given by the prefix of the string: "0x" for
+    ## hexadecimal, "0o" or "0" for octal, "0b" for binary, else decimal.
+    # integer_base = 0
+
+    ## How floats are converted to integer and unsigned, one of "round",
+    ## "truncate", "floor" or "ceil".
+    # float_to_integer = "round"
+
+    ## Number of decimal places floats are rounded to when converted to float.
+    # float_precision = 2
 `
 
 type Conversion struct {
-	Tag      []string `toml:"tag"`
-	String   []string `toml:"string"`
-	Integer  []string `toml:"integer"`
-	Unsigned []string `toml:"unsigned"`
-	Boolean  []string `toml:"boolean"`
-	Float    []string `toml:"float"`
+	Measurement []string `toml:"measurement"`
+	Tag         []string `toml:"tag"`
+	String      []string `toml:"string"`
+	Integer     []string `toml:"integer"`
+	Unsigned    []string `toml:"unsigned"`
+	Boolean     []string `toml:"boolean"`
+	Float       []string `toml:"float"`
+	Timestamp   []string `toml:"timestamp"`
+
+	TimestampFormat string `toml:"timestamp_format"`
+	Timezone        string `toml:"timezone"`
+	IntegerBase     int    `toml:"integer_base"`
+	FloatToInteger  string `toml:"float_to_integer"`
+	FloatPrecision  *int   `toml:"float_precision"`
 }
 
 type Converter struct {
-	Tags   *Conversion `toml:"tags"`
-	Fields *Conversion `toml:"fields"`
+	MeasurementTag string      `toml:"measurement_tag"`
+	Tags           *Conversion `toml:"tags"`
+	Fields         *Conversion `toml:"fields"`
 
 	initialized      bool
 	tagConversions   *ConversionFilter
@@ -57,12 +99,20 @@ type Converter struct {
 }
 
 type ConversionFilter struct {
-	Tag      filter.Filter
-	String   filter.Filter
-	Integer  filter.Filter
-	Unsigned filter.Filter
-	Boolean  filter.Filter
-	Float    filter.Filter
+	Measurement filter.Filter
+	Tag         filter.Filter
+	String      filter.Filter
+	Integer     filter.Filter
+	Unsigned    filter.Filter
+	Boolean     filter.Filter
+	Float       filter.Filter
+	Timestamp   filter.Filter
+
+	timestampFormat string
+	location        *time.Location
+	base            int
+	round           func(float64) float64
+	precision       *int
 }
 
 func (p *Converter) SampleConfig() string {
@@ -83,6 +133,9 @@ func (p *Converter) Apply(metrics ...telegraf.Metric) []telegraf.Metric {
 	}
 
 	for _, metric := range metrics {
+		if p.MeasurementTag != "" {
+			metric.AddTag(p.MeasurementTag, metric.Name())
+		}
 		p.convertTags(metric)
 		p.convertFields(metric)
 	}
@@ -100,7 +153,7 @@ func (p *Converter) compile() error {
 		return err
 	}
 
-	if tf == nil && ff == nil {
+	if tf == nil && ff == nil && p.MeasurementTag == "" {
 		return fmt.Errorf("no filters found")
 	}
 
@@ -117,6 +170,11 @@ func compileFilter(conv *Conversion) (*ConversionFilter, error) {
 
 	var err error
 	cf := &ConversionFilter{}
+	cf.Measurement, err = filter.Compile(conv.Measurement)
+	if err != nil {
+		return nil, err
+	}
+
 	cf.Tag, err = filter.Compile(conv.Tag)
 	if err != nil {
 		return nil, err
@@ -147,6 +205,48 @@ func compileFilter(conv *Conversion) (*ConversionFilter, error) {
 		return nil, err
 	}
 
+	cf.Timestamp, err = filter.Compile(conv.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	cf.timestampFormat = conv.TimestampFormat
+	if cf.timestampFormat == "" {
+		cf.timestampFormat = "unix"
+	}
+
+	timezone := conv.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	cf.location, err = time.LoadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	if conv.IntegerBase != 0 && (conv.IntegerBase < 2 || conv.IntegerBase > 36) {
+		return nil, fmt.Errorf("invalid integer_base: %d", conv.IntegerBase)
+	}
+	cf.base = conv.IntegerBase
+
+	switch conv.FloatToInteger {
+	case "round", "":
+		cf.round = Round
+	case "truncate":
+		cf.round = math.Trunc
+	case "floor":
+		cf.round = math.Floor
+	case "ceil":
+		cf.round = math.Ceil
+	default:
+		return nil, fmt.Errorf("invalid float_to_integer: %s", conv.FloatToInteger)
+	}
+
+	if conv.FloatPrecision != nil && *conv.FloatPrecision < 0 {
+		return nil, fmt.Errorf("invalid float_precision: %d", *conv.FloatPrecision)
+	}
+	cf.precision = conv.FloatPrecision
+
 	return cf, nil
 }
 
@@ -157,6 +257,16 @@ func (p *Converter) convertTags(metric telegraf.Metric) {
 	}
 
 	for key, value := range metric.Tags() {
+		if p.tagConversions.Measurement != nil && p.tagConversions.Measurement.Match(key) {
+			metric.RemoveTag(key)
+			if value == "" {
+				logPrintf("error converting to measurement [%T]: %v\n", value, value)
+				continue
+			}
+			metric.SetName(value)
+			continue
+		}
+
 		if p.tagConversions.String != nil && p.tagConversions.String.Match(key) {
 			metric.RemoveTag(key)
 			metric.AddField(key, value)
@@ -164,7 +274,7 @@ func (p *Converter) convertTags(metric telegraf.Metric) {
 		}
 
 		if p.tagConversions.Integer != nil && p.tagConversions.Integer.Match(key) {
-			v, ok := toInteger(value)
+			v, ok := p.tagConversions.toInteger(value)
 			if !ok {
 				metric.RemoveTag(key)
 				logPrintf("error converting to integer [%T]: %v\n", value, value)
@@ -176,7 +286,7 @@ func (p *Converter) convertTags(metric telegraf.Metric) {
 		}
 
 		if p.tagConversions.Unsigned != nil && p.tagConversions.Unsigned.Match(key) {
-			v, ok := toUnsigned(value)
+			v, ok := p.tagConversions.toUnsigned(value)
 			if !ok {
 				metric.RemoveTag(key)
 				logPrintf("error converting to unsigned [%T]: %v\n", value, value)
@@ -202,7 +312,7 @@ func (p *Converter) convertTags(metric telegraf.Metric) {
 		}
 
 		if p.tagConversions.Float != nil && p.tagConversions.Float.Match(key) {
-			v, ok := toFloat(value)
+			v, ok := p.tagConversions.toFloat(value)
 			if !ok {
 				metric.RemoveTag(key)
 				logPrintf("error converting to float [%T]: %v\n", value, value)
@@ -213,6 +323,19 @@ func (p *Converter) convertTags(metric telegraf.Metric) {
 			metric.AddField(key, v)
 			continue
 		}
+
+		if p.tagConversions.Timestamp != nil && p.tagConversions.Timestamp.Match(key) {
+			v, ok := p.tagConversions.toTime(value)
+			if !ok {
+				metric.RemoveTag(key)
+				logPrintf("error converting to timestamp [%T]: %v\n", value, value)
+				continue
+			}
+
+			metric.RemoveTag(key)
+			metric.SetTime(v)
+			continue
+		}
 	}
 }
 
@@ -223,6 +346,17 @@ func (p *Converter) convertFields(metric telegraf.Metric) {
 	}
 
 	for key, value := range metric.Fields() {
+		if p.fieldConversions.Measurement != nil && p.fieldConversions.Measurement.Match(key) {
+			v, ok := toString(value)
+			metric.RemoveField(key)
+			if !ok || v == "" {
+				logPrintf("error converting to measurement [%T]: %v\n", value, value)
+				continue
+			}
+			metric.SetName(v)
+			continue
+		}
+
 		if p.fieldConversions.Tag != nil && p.fieldConversions.Tag.Match(key) {
 			v, ok := toString(value)
 			if !ok {
@@ -237,7 +371,7 @@ func (p *Converter) convertFields(metric telegraf.Metric) {
 		}
 
 		if p.fieldConversions.Float != nil && p.fieldConversions.Float.Match(key) {
-			v, ok := toFloat(value)
+			v, ok := p.fieldConversions.toFloat(value)
 			if !ok {
 				metric.RemoveField(key)
 				logPrintf("error converting to integer [%T]: %v\n", value, value)
@@ -250,7 +384,7 @@ func (p *Converter) convertFields(metric telegraf.Metric) {
 		}
 
 		if p.fieldConversions.Integer != nil && p.fieldConversions.Integer.Match(key) {
-			v, ok := toInteger(value)
+			v, ok := p.fieldConversions.toInteger(value)
 			if !ok {
 				metric.RemoveField(key)
 				logPrintf("error converting to integer [%T]: %v\n", value, value)
@@ -263,7 +397,7 @@ func (p *Converter) convertFields(metric telegraf.Metric) {
 		}
 
 		if p.fieldConversions.Unsigned != nil && p.fieldConversions.Unsigned.Match(key) {
-			v, ok := toUnsigned(value)
+			v, ok := p.fieldConversions.toUnsigned(value)
 			if !ok {
 				metric.RemoveField(key)
 				logPrintf("error converting to unsigned [%T]: %v\n", value, value)
@@ -300,7 +434,150 @@ func (p *Converter) convertFields(metric telegraf.Metric) {
 			metric.AddField(key, v)
 			continue
 		}
+
+		if p.fieldConversions.Timestamp != nil && p.fieldConversions.Timestamp.Match(key) {
+			v, ok := p.fieldConversions.toTime(value)
+			if !ok {
+				metric.RemoveField(key)
+				logPrintf("error converting to timestamp [%T]: %v\n", value, value)
+				continue
+			}
+
+			metric.RemoveField(key)
+			metric.SetTime(v)
+			continue
+		}
+	}
+}
+
+// toInteger converts the value using the base and float rounding options.
+func (cf *ConversionFilter) toInteger(v interface{}) (int64, bool) {
+	switch value := v.(type) {
+	case float64:
+		return toInteger(cf.round(value))
+	case string:
+		if result, ok := parseInteger(value, cf.base); ok {
+			return result, true
+		}
+		result, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, false
+		}
+		return toInteger(cf.round(result))
+	}
+	return toInteger(v)
+}
+
+// toUnsigned converts the value using the base and float rounding options.
+func (cf *ConversionFilter) toUnsigned(v interface{}) (uint64, bool) {
+	switch value := v.(type) {
+	case float64:
+		return toUnsigned(cf.round(value))
+	case string:
+		if result, ok := parseInteger(value, cf.base); ok {
+			return toUnsigned(result)
+		}
+		if result, ok := parseUnsigned(value, cf.base); ok {
+			return result, true
+		}
+		result, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, false
+		}
+		return toUnsigned(cf.round(result))
+	}
+	return toUnsigned(v)
+}
+
+// toFloat converts the value, rounding it to the precision if set.
+func (cf *ConversionFilter) toFloat(v interface{}) (float64, bool) {
+	result, ok := toFloat(v)
+	if !ok || cf.precision == nil {
+		return result, ok
+	}
+
+	scale := math.Pow(10, float64(*cf.precision))
+	return Round(result*scale) / scale, true
+}
+
+// toTime converts the value to a time using the timestamp format.
+func (cf *ConversionFilter) toTime(v interface{}) (time.Time, bool) {
+	var unit time.Duration
+	switch cf.timestampFormat {
+	case "unix":
+		unit = time.Second
+	case "unix_ms":
+		unit = time.Millisecond
+	case "unix_us":
+		unit = time.Microsecond
+	case "unix_ns":
+		unit = time.Nanosecond
+	default:
+		value, ok := v.(string)
+		if !ok {
+			return time.Time{}, false
+		}
+		result, err := time.ParseInLocation(cf.timestampFormat, value, cf.location)
+		return result, err == nil
+	}
+
+	// Integers are converted exactly, floats may have a fractional part.
+	switch value := v.(type) {
+	case int64:
+		return time.Unix(0, value*int64(unit)), true
+	case uint64:
+		return time.Unix(0, int64(value)*int64(unit)), true
+	case string:
+		if result, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return time.Unix(0, result*int64(unit)), true
+		}
+	}
+
+	f, ok := toFloat(v)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(0, int64(Round(f*float64(unit)))), true
+}
+
+// parseInteger parses a string in the base.  With a base of 0 the base is
+// given by the prefix of the string, the "0b" and "0o" prefixes are handled
+// here as they are not supported by strconv in older Go versions.
+func parseInteger(s string, base int) (int64, bool) {
+	sign, digits, base := splitPrefix(s, base)
+	result, err := strconv.ParseInt(sign+digits, base, 64)
+	return result, err == nil
+}
+
+func parseUnsigned(s string, base int) (uint64, bool) {
+	sign, digits, base := splitPrefix(s, base)
+	if sign != "" && sign != "+" {
+		return 0, false
+	}
+	result, err := strconv.ParseUint(digits, base, 64)
+	return result, err == nil
+}
+
+func splitPrefix(s string, base int) (string, string, int) {
+	var sign string
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], s[1:]
+	}
+
+	var prefix string
+	if len(s) > 2 {
+		prefix = strings.ToLower(s[:2])
+	}
+
+	switch {
+	case prefix == "0b" && (base == 0 || base == 2):
+		return sign, s[2:], 2
+	case prefix == "0o" && (base == 0 || base == 8):
+		return sign, s[2:], 8
+	case prefix == "0x" && base == 16:
+		return sign, s[2:], 16
 	}
+	return sign, s, base
 }
 
 func toBool(v interface{}) (bool, bool) {
@@ -476,6 +476,290 @@ func TestConverter(t *testing.T) {
 				),
 			),
 		},
+		{
+			name: "measurement name to tag",
+			converter: &Converter{
+				MeasurementTag: "measurement",
+			},
+			input: Metric(
+				metric.New(
+					"cpu",
+					map[string]string{},
+					map[string]interface{}{
+						"value": 42.0,
+					},
+					time.Unix(0, 0),
+				),
+			),
+			expected: Metric(
+				metric.New(
+					"cpu",
+					map[string]string{
+						"measurement": "cpu",
+					},
+					map[string]interface{}{
+						"value": 42.0,
+					},
+					time.Unix(0, 0),
+				),
+			),
+		},
+		{
+			name: "tag and field to measurement name",
+			converter: &Converter{
+				Tags: &Conversion{
+					Measurement: []string{"name"},
+				},
+				Fields: &Conversion{
+					Measurement: []string{"type"},
+				},
+			},
+			input: Metric(
+				metric.New(
+					"cpu",
+					map[string]string{
+						"name": "from_tag",
+					},
+					map[string]interface{}{
+						"value": 42.0,
+						"type":  "from_field",
+					},
+					time.Unix(0, 0),
+				),
+			),
+			expected: Metric(
+				metric.New(
+					"from_field",
+					map[string]string{},
+					map[string]interface{}{
+						"value": 42.0,
+					},
+					time.Unix(0, 0),
+				),
+			),
+		},
+		{
+			name: "field to timestamp",
+			converter: &Converter{
+				Fields: &Conversion{
+					Timestamp:       []string{"time"},
+					TimestampFormat: "unix_ms",
+				},
+			},
+			input: Metric(
+				metric.New(
+					"cpu",
+					map[string]string{},
+					map[string]interface{}{
+						"value": 42.0,
+						"time":  int64(1560000000123),
+					},
+					time.Unix(0, 0),
+				),
+			),
+			expected: Metric(
+				metric.New(
+					"cpu",
+					map[string]string{},
+					map[string]interface{}{
+						"value": 42.0,
+					},
+					time.Unix(1560000000, 123000000),
+				),
+			),
+		},
+		{
+			name: "tag to timestamp with layout and timezone",
+			converter: &Converter{
+				Tags: &Conversion{
+					Timestamp:       []string{"date"},
+					TimestampFormat: "2006-01-02 15:04:05",
+					Timezone:        "America/New_York",
+				},
+			},
+			input: Metric(
+				metric.New(
+					"cpu",
+					map[string]string{
+						"date": "2019-06-08 09:20:00",
+					},
+					map[string]interface{}{
+						"value": 42.0,
+					},
+					time.Unix(0, 0),
+				),
+			),
+			expected: Metric(
+				metric.New(
+					"cpu",
+					map[string]string{},
+					map[string]interface{}{
+						"value": 42.0,
+					},
+					time.Date(2019, time.June, 8, 9, 20, 0, 0, mustLoadLocation("America/New_York")),
+				),
+			),
+		},
+		{
+			name: "invalid timestamp is dropped",
+			converter: &Converter{
+				Fields: &Conversion{
+					Timestamp: []string{"time"},
+				},
+			},
+			input: Metric(
+				metric.New(
+					"cpu",
+					map[string]string{},
+					map[string]interface{}{
+						"value": 42.0,
+						"time":  "yesterday",
+					},
+					time.Unix(0, 0),
+				),
+			),
+			expected: Metric(
+				metric.New(
+					"cpu",
+					map[string]string{},
+					map[string]interface{}{
+						"value": 42.0,
+					},
+					time.Unix(0, 0),
+				),
+			),
+		},
+		{
+			name: "base prefixed integers",
+			converter: &Converter{
+				Fields: &Conversion{
+					Integer:  []string{"int_*"},
+					Unsigned: []string{"uint_*"},
+				},
+			},
+			input: Metric(
+				metric.New(
+					"cpu",
+					map[string]string{},
+					map[string]interface{}{
+						"int_hex":  "0x1F",
+						"int_bin":  "-0b101",
+						"int_oct":  "0o17",
+						"uint_hex": "0xffffffffffffffff",
+					},
+					time.Unix(0, 0),
+				),
+			),
+			expected: Metric(
+				metric.New(
+					"cpu",
+					map[string]string{},
+					map[string]interface{}{
+						"int_hex":  int64(31),
+						"int_bin":  int64(-5),
+						"int_oct":  int64(15),
+						"uint_hex": uint64(math.MaxUint64),
+					},
+					time.Unix(0, 0),
+				),
+			),
+		},
+		{
+			name: "integer base",
+			converter: &Converter{
+				Fields: &Conversion{
+					Unsigned:    []string{"*"},
+					IntegerBase: 16,
+				},
+			},
+			input: Metric(
+				metric.New(
+					"snmp",
+					map[string]string{},
+					map[string]interface{}{
+						"a": "ff",
+						"b": "0x10",
+					},
+					time.Unix(0, 0),
+				),
+			),
+			expected: Metric(
+				metric.New(
+					"snmp",
+					map[string]string{},
+					map[string]interface{}{
+						"a": uint64(255),
+						"b": uint64(16),
+					},
+					time.Unix(0, 0),
+				),
+			),
+		},
+		{
+			name: "float to integer truncation",
+			converter: &Converter{
+				Fields: &Conversion{
+					Integer:        []string{"int"},
+					Unsigned:       []string{"uint"},
+					FloatToInteger: "truncate",
+				},
+			},
+			input: Metric(
+				metric.New(
+					"cpu",
+					map[string]string{},
+					map[string]interface{}{
+						"int":  -2.7,
+						"uint": "2.7",
+					},
+					time.Unix(0, 0),
+				),
+			),
+			expected: Metric(
+				metric.New(
+					"cpu",
+					map[string]string{},
+					map[string]interface{}{
+						"int":  int64(-2),
+						"uint": uint64(2),
+					},
+					time.Unix(0, 0),
+				),
+			),
+		},
+		{
+			name: "float precision",
+			converter: &Converter{
+				Fields: &Conversion{
+					Float:          []string{"*"},
+					FloatPrecision: intPtr(2),
+				},
+			},
+			input: Metric(
+				metric.New(
+					"cpu",
+					map[string]string{},
+					map[string]interface{}{
+						"a": 0.123456,
+						"b": "2.345",
+						"c": int64(3),
+					},
+					time.Unix(0, 0),
+				),
+			),
+			expected: Metric(
+				metric.New(
+					"cpu",
+					map[string]string{},
+					map[string]interface{}{
+						"a": 0.12,
+						"b": 2.35,
+						"c": 3.0,
+					},
+					time.Unix(0, 0),
+				),
+			),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -489,3 +773,45 @@ func TestConverter(t *testing.T) {
 		})
 	}
 }
+
+func TestInvalidConversion(t *testing.T) {
+	tests := []struct {
+		name       string
+		conversion *Conversion
+	}{
+		{
+			name:       "invalid timezone",
+			conversion: &Conversion{Timezone: "Mars/Olympus_Mons"},
+		},
+		{
+			name:       "invalid integer base",
+			conversion: &Conversion{IntegerBase: 1},
+		},
+		{
+			name:       "invalid float to integer",
+			conversion: &Conversion{FloatToInteger: "nearest"},
+		},
+		{
+			name:       "invalid float precision",
+			conversion: &Conversion{FloatPrecision: intPtr(-1)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter := &Converter{Fields: tt.conversion}
+			require.Error(t, converter.compile())
+		})
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
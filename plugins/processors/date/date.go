@@ -0,0 +1,137 @@
+package date
+
+import (
+	"errors"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+const sampleConfig = `
+  ## New tag to create
+  tag_key = "month"
+
+  ## New field to create (cannot set both field_key and tag_key)
+  # field_key = "month"
+
+  ## Date format string, must be a representation of the Go "reference time"
+  ## which is "Mon Jan 2 15:04:05 -0700 MST 2006".
+  date_format = "Jan"
+
+  ## If destination is a field, date format can also be one of
+  ## "unix", "unix_ms", "unix_us", or "unix_ns", which will insert an integer field.
+  # date_format = "unix"
+
+  ## Offset duration added to the metric time before it is formatted.
+  # date_offset = "0s"
+
+  ## Timezone to use when creating the tag or field using a reference time
+  ## string.  This can be set to one of "UTC", "Local", or to a location name
+  ## in the IANA Time Zone database.
+  ##   example: timezone = "America/Los_Angeles"
+  # timezone = "UTC"
+
+  ## New field to create with the difference in seconds between the time the
+  ## metric is processed and the metric time, useful to measure the delay
+  ## before metrics are ingested.
+  # offset_field_key = "delay"
+`
+
+type Date struct {
+	TagKey         string            `toml:"tag_key"`
+	FieldKey       string            `toml:"field_key"`
+	DateFormat     string            `toml:"date_format"`
+	DateOffset     internal.Duration `toml:"date_offset"`
+	Timezone       string            `toml:"timezone"`
+	OffsetFieldKey string            `toml:"offset_field_key"`
+
+	location *time.Location
+	now      func() time.Time
+}
+
+func (d *Date) SampleConfig() string {
+	return sampleConfig
+}
+
+func (d *Date) Description() string {
+	return "Add a tag or field derived from the timestamp of the metric"
+}
+
+func (d *Date) Init() error {
+	if d.TagKey != "" && d.FieldKey != "" {
+		return errors.New("only one of tag_key or field_key can be set")
+	}
+
+	if d.TagKey == "" && d.FieldKey == "" && d.OffsetFieldKey == "" {
+		return errors.New("one of tag_key, field_key or offset_field_key must be set")
+	}
+
+	if (d.TagKey != "" || d.FieldKey != "") && d.DateFormat == "" {
+		return errors.New("date_format is required")
+	}
+
+	if d.TagKey != "" && isUnixFormat(d.DateFormat) {
+		return errors.New("date_format " + d.DateFormat + " can only be used with field_key")
+	}
+
+	location, err := time.LoadLocation(d.Timezone)
+	if err != nil {
+		return err
+	}
+	d.location = location
+	return nil
+}
+
+func (d *Date) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	for _, point := range in {
+		tm := point.Time().Add(d.DateOffset.Duration).In(d.location)
+
+		if d.TagKey != "" {
+			point.AddTag(d.TagKey, tm.Format(d.DateFormat))
+		} else if d.FieldKey != "" {
+			point.AddField(d.FieldKey, d.formatField(tm))
+		}
+
+		if d.OffsetFieldKey != "" {
+			point.AddField(d.OffsetFieldKey, d.now().Sub(point.Time()).Seconds())
+		}
+	}
+
+	return in
+}
+
+// formatField returns the time as an integer for the unix formats, or
+// formatted as a string for a reference time layout.
+func (d *Date) formatField(tm time.Time) interface{} {
+	switch d.DateFormat {
+	case "unix":
+		return tm.Unix()
+	case "unix_ms":
+		return tm.UnixNano() / int64(time.Millisecond)
+	case "unix_us":
+		return tm.UnixNano() / int64(time.Microsecond)
+	case "unix_ns":
+		return tm.UnixNano()
+	default:
+		return tm.Format(d.DateFormat)
+	}
+}
+
+func isUnixFormat(format string) bool {
+	switch format {
+	case "unix", "unix_ms", "unix_us", "unix_ns":
+		return true
+	}
+	return false
+}
+
+func init() {
+	processors.Add("date", func() telegraf.Processor {
+		return &Date{
+			Timezone: "UTC",
+			now:      time.Now,
+		}
+	})
+}
@@ -0,0 +1,174 @@
+package date
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newMetric(tm time.Time) telegraf.Metric {
+	return testutil.MustMetric("cpu",
+		map[string]string{},
+		map[string]interface{}{"value": 42},
+		tm,
+	)
+}
+
+func newDate() *Date {
+	return &Date{
+		Timezone: "UTC",
+		now:      time.Now,
+	}
+}
+
+func TestMonthTag(t *testing.T) {
+	d := newDate()
+	d.TagKey = "month"
+	d.DateFormat = "Jan"
+
+	tm := time.Date(2019, time.March, 4, 5, 6, 7, 0, time.UTC)
+	require.NoError(t, d.Init())
+	actual := d.Apply(newMetric(tm))
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{"month": "Mar"},
+			map[string]interface{}{"value": 42},
+			tm,
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestWeekdayAndHourInTimezone(t *testing.T) {
+	d := newDate()
+	d.TagKey = "weekday_hour"
+	d.DateFormat = "Monday 15"
+	d.Timezone = "Asia/Tokyo"
+
+	// Sunday 23:00 UTC is Monday 08:00 in Tokyo.
+	tm := time.Date(2019, time.March, 3, 23, 0, 0, 0, time.UTC)
+	require.NoError(t, d.Init())
+	actual := d.Apply(newMetric(tm))
+
+	require.Equal(t, "Monday 08", actual[0].Tags()["weekday_hour"])
+}
+
+func TestDateOffset(t *testing.T) {
+	d := newDate()
+	d.TagKey = "hour"
+	d.DateFormat = "15"
+	d.DateOffset = internal.Duration{Duration: 2 * time.Hour}
+
+	tm := time.Date(2019, time.March, 4, 5, 6, 7, 0, time.UTC)
+	require.NoError(t, d.Init())
+	actual := d.Apply(newMetric(tm))
+
+	require.Equal(t, "07", actual[0].Tags()["hour"])
+	require.Equal(t, tm, actual[0].Time())
+}
+
+func TestFieldFormats(t *testing.T) {
+	tm := time.Date(2019, time.March, 4, 5, 6, 7, 8, time.UTC)
+
+	var tests = []struct {
+		format   string
+		expected interface{}
+	}{
+		{
+			format:   "2006-01-02",
+			expected: "2019-03-04",
+		},
+		{
+			format:   "unix",
+			expected: tm.Unix(),
+		},
+		{
+			format:   "unix_ms",
+			expected: tm.UnixNano() / int64(time.Millisecond),
+		},
+		{
+			format:   "unix_us",
+			expected: tm.UnixNano() / int64(time.Microsecond),
+		},
+		{
+			format:   "unix_ns",
+			expected: tm.UnixNano(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			d := newDate()
+			d.FieldKey = "date"
+			d.DateFormat = tt.format
+
+			require.NoError(t, d.Init())
+			actual := d.Apply(newMetric(tm))
+
+			value, ok := actual[0].GetField("date")
+			require.True(t, ok)
+			require.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestOffsetField(t *testing.T) {
+	now := time.Date(2019, time.March, 4, 5, 6, 7, 0, time.UTC)
+
+	d := newDate()
+	d.OffsetFieldKey = "delay"
+	d.now = func() time.Time {
+		return now
+	}
+
+	require.NoError(t, d.Init())
+	actual := d.Apply(newMetric(now.Add(-1500 * time.Millisecond)))
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{"value": 42, "delay": 1.5},
+			now.Add(-1500*time.Millisecond),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestInvalidConfig(t *testing.T) {
+	var tests = []struct {
+		name string
+		date *Date
+	}{
+		{
+			name: "no destination",
+			date: &Date{DateFormat: "Jan", Timezone: "UTC"},
+		},
+		{
+			name: "tag and field",
+			date: &Date{TagKey: "a", FieldKey: "b", DateFormat: "Jan", Timezone: "UTC"},
+		},
+		{
+			name: "no format",
+			date: &Date{TagKey: "a", Timezone: "UTC"},
+		},
+		{
+			name: "unix format for tag",
+			date: &Date{TagKey: "a", DateFormat: "unix", Timezone: "UTC"},
+		},
+		{
+			name: "unknown timezone",
+			date: &Date{TagKey: "a", DateFormat: "Jan", Timezone: "Nowhere/Special"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Error(t, tt.date.Init())
+		})
+	}
+}
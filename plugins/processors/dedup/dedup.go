@@ -0,0 +1,112 @@
+package dedup
+
+import (
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+var sampleConfig = `
+  ## Maximum time to suppress output
+  dedup_interval = "600s"
+`
+
+type Dedup struct {
+	DedupInterval internal.Duration `toml:"dedup_interval"`
+
+	// latest is the newest metric time seen, all expiry is based on metric
+	// time so that replayed or delayed metrics are handled consistently.
+	latest    time.Time
+	flushTime time.Time
+	cache     map[uint64]*entry
+}
+
+// entry holds the last emitted values of a series.
+type entry struct {
+	fields map[string]interface{}
+	time   time.Time
+}
+
+func (d *Dedup) SampleConfig() string {
+	return sampleConfig
+}
+
+func (d *Dedup) Description() string {
+	return "Filter metrics with repeating field values"
+}
+
+func (d *Dedup) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	for _, metric := range in {
+		if metric.Time().After(d.latest) {
+			d.latest = metric.Time()
+		}
+	}
+	d.cleanup()
+
+	out := in[:0]
+	for _, metric := range in {
+		id := metric.HashID()
+		if d.isDuplicate(id, metric) {
+			metric.Drop()
+			continue
+		}
+
+		d.cache[id] = &entry{
+			fields: metric.Fields(),
+			time:   metric.Time(),
+		}
+		out = append(out, metric)
+	}
+	return out
+}
+
+// isDuplicate returns true if the metric has the same fields and values as
+// those last emitted for the series within the dedup interval.
+func (d *Dedup) isDuplicate(id uint64, metric telegraf.Metric) bool {
+	e, ok := d.cache[id]
+	if !ok {
+		return false
+	}
+
+	if metric.Time().Sub(e.time) >= d.DedupInterval.Duration {
+		return false
+	}
+
+	if len(metric.FieldList()) != len(e.fields) {
+		return false
+	}
+
+	for _, field := range metric.FieldList() {
+		value, ok := e.fields[field.Key]
+		if !ok || value != field.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// cleanup removes the series which have not been emitted within the dedup
+// interval of the newest metric, it runs at most once per interval.
+func (d *Dedup) cleanup() {
+	if d.latest.Sub(d.flushTime) < d.DedupInterval.Duration {
+		return
+	}
+	d.flushTime = d.latest
+
+	for id, e := range d.cache {
+		if d.latest.Sub(e.time) >= d.DedupInterval.Duration {
+			delete(d.cache, id)
+		}
+	}
+}
+
+func init() {
+	processors.Add("dedup", func() telegraf.Processor {
+		return &Dedup{
+			DedupInterval: internal.Duration{Duration: 10 * time.Minute},
+			cache:         make(map[uint64]*entry),
+		}
+	})
+}
@@ -0,0 +1,198 @@
+package dedup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/processors"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newDedup() *Dedup {
+	return processors.Processors["dedup"]().(processors.Unwrapper).Unwrap().(*Dedup)
+}
+
+func newMetric(tags map[string]string, value interface{}, tm time.Time) telegraf.Metric {
+	return testutil.MustMetric("cpu", tags, map[string]interface{}{"value": value}, tm)
+}
+
+func TestDedup(t *testing.T) {
+	now := time.Now()
+	host := map[string]string{"host": "a"}
+	other := map[string]string{"host": "b"}
+
+	var tests = []struct {
+		name     string
+		input    []telegraf.Metric
+		expected []telegraf.Metric
+	}{
+		{
+			name: "suppress repeated values",
+			input: []telegraf.Metric{
+				newMetric(host, 1, now),
+				newMetric(host, 1, now.Add(time.Minute)),
+				newMetric(host, 1, now.Add(2*time.Minute)),
+			},
+			expected: []telegraf.Metric{
+				newMetric(host, 1, now),
+			},
+		},
+		{
+			name: "emit changed values",
+			input: []telegraf.Metric{
+				newMetric(host, 1, now),
+				newMetric(host, 2, now.Add(time.Minute)),
+				newMetric(host, 2, now.Add(2*time.Minute)),
+				newMetric(host, 1, now.Add(3*time.Minute)),
+			},
+			expected: []telegraf.Metric{
+				newMetric(host, 1, now),
+				newMetric(host, 2, now.Add(time.Minute)),
+				newMetric(host, 1, now.Add(3*time.Minute)),
+			},
+		},
+		{
+			name: "emit after dedup interval",
+			input: []telegraf.Metric{
+				newMetric(host, 1, now),
+				newMetric(host, 1, now.Add(5*time.Minute)),
+				newMetric(host, 1, now.Add(10*time.Minute)),
+				newMetric(host, 1, now.Add(15*time.Minute)),
+			},
+			expected: []telegraf.Metric{
+				newMetric(host, 1, now),
+				newMetric(host, 1, now.Add(10*time.Minute)),
+			},
+		},
+		{
+			name: "separate series",
+			input: []telegraf.Metric{
+				newMetric(host, 1, now),
+				newMetric(other, 1, now),
+				newMetric(host, 1, now.Add(time.Minute)),
+				newMetric(other, 1, now.Add(time.Minute)),
+			},
+			expected: []telegraf.Metric{
+				newMetric(host, 1, now),
+				newMetric(other, 1, now),
+			},
+		},
+		{
+			name: "changed field type",
+			input: []telegraf.Metric{
+				newMetric(host, 1, now),
+				newMetric(host, 1.0, now.Add(time.Minute)),
+			},
+			expected: []telegraf.Metric{
+				newMetric(host, 1, now),
+				newMetric(host, 1.0, now.Add(time.Minute)),
+			},
+		},
+		{
+			name: "new field",
+			input: []telegraf.Metric{
+				newMetric(host, 1, now),
+				testutil.MustMetric("cpu", host,
+					map[string]interface{}{"value": 1, "other": 2},
+					now.Add(time.Minute),
+				),
+			},
+			expected: []telegraf.Metric{
+				newMetric(host, 1, now),
+				testutil.MustMetric("cpu", host,
+					map[string]interface{}{"value": 1, "other": 2},
+					now.Add(time.Minute),
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newDedup()
+
+			var actual []telegraf.Metric
+			for _, m := range tt.input {
+				actual = append(actual, d.Apply(m)...)
+			}
+
+			testutil.RequireMetricsEqual(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestDedupBatch(t *testing.T) {
+	now := time.Now()
+	host := map[string]string{"host": "a"}
+
+	d := newDedup()
+	actual := d.Apply(
+		newMetric(host, 1, now),
+		newMetric(host, 1, now.Add(time.Minute)),
+		newMetric(host, 2, now.Add(2*time.Minute)),
+	)
+
+	expected := []telegraf.Metric{
+		newMetric(host, 1, now),
+		newMetric(host, 2, now.Add(2*time.Minute)),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestDedupCleanup(t *testing.T) {
+	now := time.Now()
+
+	d := newDedup()
+	d.Apply(newMetric(map[string]string{"host": "a"}, 1, now.Add(-time.Hour)))
+	d.Apply(newMetric(map[string]string{"host": "b"}, 1, now))
+	require.Len(t, d.cache, 1)
+}
+
+func TestDedupCleanupUsesMetricTime(t *testing.T) {
+	past := time.Unix(0, 0)
+	host := map[string]string{"host": "a"}
+
+	d := newDedup()
+	actual := d.Apply(newMetric(host, 1, past))
+	actual = append(actual, d.Apply(newMetric(host, 1, past.Add(time.Minute)))...)
+
+	expected := []telegraf.Metric{
+		newMetric(host, 1, past),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+	require.Len(t, d.cache, 1)
+}
+
+func TestDedupSubsetOfFields(t *testing.T) {
+	now := time.Now()
+	tags := map[string]string{"host": "a"}
+	all := testutil.MustMetric("cpu", tags,
+		map[string]interface{}{"user": 1, "system": 2}, now)
+	subset := testutil.MustMetric("cpu", tags,
+		map[string]interface{}{"user": 1}, now.Add(time.Minute))
+
+	d := newDedup()
+	actual := d.Apply(all.Copy(), subset.Copy())
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{all, subset}, actual)
+}
+
+func TestDedupTracking(t *testing.T) {
+	var delivered int
+	notify := func(telegraf.DeliveryInfo) {
+		delivered++
+	}
+
+	now := time.Now()
+	host := map[string]string{"host": "a"}
+	m1, _ := metric.WithTracking(newMetric(host, 1, now), notify)
+	m2, _ := metric.WithTracking(newMetric(host, 1, now.Add(time.Minute)), notify)
+
+	d := newDedup()
+	for _, m := range d.Apply(m1, m2) {
+		m.Accept()
+	}
+	require.Equal(t, 2, delivered)
+}
@@ -2,6 +2,8 @@ package enum
 
 import (
 	"fmt"
+	"log"
+	"regexp"
 	"strconv"
 
 	"github.com/influxdata/telegraf"
@@ -20,9 +22,14 @@ var sampleConfig = `
     ## source tag or field is used, overwriting the original value.
     dest = "status_code"
 
+    ## Kind of the destination, "tag" or "field".  By default the destination
+    ## is of the same kind as the source, set it to map a field into a tag or
+    ## a tag into a field.
+    # dest_type = "field"
+
     ## Default value to be used for all values not contained in the mapping
     ## table.  When unset, the unmodified value for the field will be used if no
-    ## match is found.
+    ## match is found.  Numeric fields are not changed by the default.
     # default = 0
 
     ## Table of mappings
@@ -30,6 +37,24 @@ var sampleConfig = `
       green = 1
       amber = 2
       red = 3
+
+    ## Mappings of numeric ranges, used if no value mapping matches.  Ranges
+    ## include min and exclude max, either may be omitted.  The first matching
+    ## range is used.
+    # [[processors.enum.mapping.range_mappings]]
+    #   max = 50
+    #   value = "ok"
+    # [[processors.enum.mapping.range_mappings]]
+    #   min = 50
+    #   max = 80
+    #   value = "warn"
+
+    ## Mappings of regular expressions, used if no value or range mapping
+    ## matches.  The first matching pattern is used.  Not used for numeric
+    ## fields.
+    # [[processors.enum.mapping.regex_mappings]]
+    #   pattern = "^5\\d\\d$"
+    #   value = "error"
 `
 
 type EnumMapper struct {
@@ -40,8 +65,24 @@ type Mapping struct {
 	Tag           string
 	Field         string
 	Dest          string
+	DestType      string `toml:"dest_type"`
 	Default       interface{}
 	ValueMappings map[string]interface{}
+	RangeMappings []RangeMapping `toml:"range_mappings"`
+	RegexMappings []RegexMapping `toml:"regex_mappings"`
+}
+
+type RangeMapping struct {
+	Min   *float64
+	Max   *float64
+	Value interface{}
+}
+
+type RegexMapping struct {
+	Pattern string
+	Value   interface{}
+
+	regex *regexp.Regexp
 }
 
 func (mapper *EnumMapper) SampleConfig() string {
@@ -52,6 +93,26 @@ func (mapper *EnumMapper) Description() string {
 	return "Map enum values according to given table."
 }
 
+func (mapper *EnumMapper) Init() error {
+	for i := range mapper.Mappings {
+		switch mapper.Mappings[i].DestType {
+		case "", "tag", "field":
+		default:
+			return fmt.Errorf("invalid dest_type: %s", mapper.Mappings[i].DestType)
+		}
+
+		for j := range mapper.Mappings[i].RegexMappings {
+			r := &mapper.Mappings[i].RegexMappings[j]
+			regex, err := regexp.Compile(r.Pattern)
+			if err != nil {
+				return fmt.Errorf("invalid pattern %q: %v", r.Pattern, err)
+			}
+			r.regex = regex
+		}
+	}
+	return nil
+}
+
 func (mapper *EnumMapper) Apply(in ...telegraf.Metric) []telegraf.Metric {
 	for i := 0; i < len(in); i++ {
 		in[i] = mapper.applyMappings(in[i])
@@ -63,22 +124,15 @@ func (mapper *EnumMapper) applyMappings(metric telegraf.Metric) telegraf.Metric
 	for _, mapping := range mapper.Mappings {
 		if mapping.Field != "" {
 			if originalValue, isPresent := metric.GetField(mapping.Field); isPresent {
-				if adjustedValue, isString := adjustBoolValue(originalValue).(string); isString {
-					if mappedValue, isMappedValuePresent := mapping.mapValue(adjustedValue); isMappedValuePresent {
-						writeField(metric, mapping.getDestination(), mappedValue)
-					}
+				if mappedValue, isMappedValuePresent := mapping.mapValue(originalValue); isMappedValuePresent {
+					mapping.write(metric, "field", mappedValue)
 				}
 			}
 		}
 		if mapping.Tag != "" {
 			if originalValue, isPresent := metric.GetTag(mapping.Tag); isPresent {
 				if mappedValue, isMappedValuePresent := mapping.mapValue(originalValue); isMappedValuePresent {
-					switch val := mappedValue.(type) {
-					case string:
-						writeTag(metric, mapping.getDestinationTag(), val)
-					default:
-						writeTag(metric, mapping.getDestinationTag(), fmt.Sprintf("%v", val))
-					}
+					mapping.write(metric, "tag", mappedValue)
 				}
 			}
 		}
@@ -86,35 +140,130 @@ func (mapper *EnumMapper) applyMappings(metric telegraf.Metric) telegraf.Metric
 	return metric
 }
 
-func adjustBoolValue(in interface{}) interface{} {
-	if mappedBool, isBool := in.(bool); isBool == true {
-		return strconv.FormatBool(mappedBool)
+// write sets the destination of the mapping to the mapped value of a source
+// of the given kind.
+func (mapping *Mapping) write(metric telegraf.Metric, source string, value interface{}) {
+	destType := source
+	if mapping.DestType != "" {
+		destType = mapping.DestType
 	}
-	return in
+
+	dest := mapping.Dest
+	if dest == "" && source == "field" {
+		dest = mapping.Field
+	}
+	if dest == "" && source == "tag" {
+		dest = mapping.Tag
+	}
+
+	switch destType {
+	case "field":
+		writeField(metric, dest, value)
+	case "tag":
+		switch val := value.(type) {
+		case string:
+			writeTag(metric, dest, val)
+		default:
+			writeTag(metric, dest, fmt.Sprintf("%v", val))
+		}
+	default:
+		log.Printf("E! [processors.enum] Invalid dest_type: %s", destType)
+	}
+}
+
+// asString returns the string representation used to match a value against
+// the value and regex mappings.
+func asString(in interface{}) (string, bool) {
+	switch value := in.(type) {
+	case string:
+		return value, true
+	case bool:
+		return strconv.FormatBool(value), true
+	case int64:
+		return strconv.FormatInt(value, 10), true
+	case uint64:
+		return strconv.FormatUint(value, 10), true
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	}
+	return "", false
 }
 
-func (mapping *Mapping) mapValue(original string) (interface{}, bool) {
-	if mapped, found := mapping.ValueMappings[original]; found == true {
+// asNumber returns the value as a float to match it against the range
+// mappings, strings are parsed.
+func asNumber(in interface{}) (float64, bool) {
+	switch value := in.(type) {
+	case int64:
+		return float64(value), true
+	case uint64:
+		return float64(value), true
+	case float64:
+		return value, true
+	case string:
+		number, err := strconv.ParseFloat(value, 64)
+		return number, err == nil
+	}
+	return 0, false
+}
+
+func (mapping *Mapping) mapValue(original interface{}) (interface{}, bool) {
+	str, isString := asString(original)
+	if !isString {
+		return original, false
+	}
+
+	if mapped, found := mapping.ValueMappings[str]; found == true {
 		return mapped, true
 	}
+
+	if number, isNumber := asNumber(original); isNumber {
+		for _, r := range mapping.RangeMappings {
+			if r.contains(number) {
+				return r.Value, true
+			}
+		}
+	}
+
+	// Numeric fields are only changed by an explicit value or range mapping,
+	// so that the type of unmapped values is kept.
+	if isNumeric(original) {
+		return original, false
+	}
+
+	for i := range mapping.RegexMappings {
+		r := &mapping.RegexMappings[i]
+		if r.matches(str) {
+			return r.Value, true
+		}
+	}
+
 	if mapping.Default != nil {
 		return mapping.Default, true
 	}
 	return original, false
 }
 
-func (mapping *Mapping) getDestination() string {
-	if mapping.Dest != "" {
-		return mapping.Dest
+func isNumeric(value interface{}) bool {
+	switch value.(type) {
+	case int64, uint64, float64:
+		return true
 	}
-	return mapping.Field
+	return false
 }
 
-func (mapping *Mapping) getDestinationTag() string {
-	if mapping.Dest != "" {
-		return mapping.Dest
+func (r *RangeMapping) contains(number float64) bool {
+	if r.Min != nil && number < *r.Min {
+		return false
+	}
+	if r.Max != nil && number >= *r.Max {
+		return false
 	}
-	return mapping.Tag
+	return true
+}
+
+// matches returns true if the pattern, compiled by Init, matches the value.
+func (r *RegexMapping) matches(value string) bool {
+	return r.regex != nil && r.regex.MatchString(value)
 }
 
 func writeField(metric telegraf.Metric, name string, value interface{}) {
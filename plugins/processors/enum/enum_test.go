@@ -123,3 +123,155 @@ func TestWritesToDestination(t *testing.T) {
 	assertFieldValue(t, "test", "string_value", fields)
 	assertFieldValue(t, 1, "string_code", fields)
 }
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func TestMapsNumericValue(t *testing.T) {
+	mapper := EnumMapper{Mappings: []Mapping{{Field: "int_value", ValueMappings: map[string]interface{}{"13": "thirteen"}}}}
+
+	fields := calculateProcessedValues(mapper, createTestMetric())
+
+	assertFieldValue(t, "thirteen", "int_value", fields)
+}
+
+func TestMapsRange(t *testing.T) {
+	mapper := EnumMapper{Mappings: []Mapping{{
+		Field: "int_value",
+		RangeMappings: []RangeMapping{
+			{Max: floatPtr(10), Value: "ok"},
+			{Min: floatPtr(10), Max: floatPtr(13), Value: "warn"},
+			{Min: floatPtr(13), Value: "crit"},
+		},
+	}}}
+
+	fields := calculateProcessedValues(mapper, createTestMetric())
+
+	assertFieldValue(t, "crit", "int_value", fields)
+}
+
+func TestValueMappingBeforeRange(t *testing.T) {
+	mapper := EnumMapper{Mappings: []Mapping{{
+		Field:         "int_value",
+		ValueMappings: map[string]interface{}{"13": "exact"},
+		RangeMappings: []RangeMapping{{Value: "range"}},
+	}}}
+
+	fields := calculateProcessedValues(mapper, createTestMetric())
+
+	assertFieldValue(t, "exact", "int_value", fields)
+}
+
+func TestRangeOnNumericTag(t *testing.T) {
+	mapper := EnumMapper{Mappings: []Mapping{{
+		Tag:           "level",
+		RangeMappings: []RangeMapping{{Min: floatPtr(0), Max: floatPtr(50), Value: "ok"}},
+	}}}
+	source := createTestMetric()
+	source.AddTag("level", "42.5")
+
+	tags := calculateProcessedTags(mapper, source)
+
+	assertTagValue(t, "ok", "level", tags)
+}
+
+func TestNoRangeMatchKeepsNumericField(t *testing.T) {
+	mapper := EnumMapper{Mappings: []Mapping{{
+		Field:         "int_value",
+		Default:       "unknown",
+		RangeMappings: []RangeMapping{{Max: floatPtr(10), Value: "ok"}},
+	}}}
+
+	fields := calculateProcessedValues(mapper, createTestMetric())
+
+	assertFieldValue(t, 13, "int_value", fields)
+}
+
+func TestDefaultNotAppliedToNumericField(t *testing.T) {
+	mapper := EnumMapper{Mappings: []Mapping{{
+		Field:         "status",
+		Default:       "other",
+		ValueMappings: map[string]interface{}{"404": "not_found"},
+		RegexMappings: []RegexMapping{{Pattern: ".*", Value: "any"}},
+	}}}
+	source := createTestMetric()
+	source.AddField("status", int64(200))
+
+	assert.NoError(t, mapper.Init())
+	fields := calculateProcessedValues(mapper, source)
+
+	assert.Equal(t, int64(200), fields["status"])
+}
+
+func TestNoRangeMatchOnNumericTagUsesDefault(t *testing.T) {
+	mapper := EnumMapper{Mappings: []Mapping{{
+		Tag:           "level",
+		Default:       "unknown",
+		RangeMappings: []RangeMapping{{Max: floatPtr(10), Value: "ok"}},
+	}}}
+	source := createTestMetric()
+	source.AddTag("level", "42.5")
+
+	tags := calculateProcessedTags(mapper, source)
+
+	assertTagValue(t, "unknown", "level", tags)
+}
+
+func TestMapsRegex(t *testing.T) {
+	mapper := EnumMapper{Mappings: []Mapping{{
+		Field: "string_value",
+		RegexMappings: []RegexMapping{
+			{Pattern: "^x", Value: "x"},
+			{Pattern: "^te", Value: "te"},
+		},
+	}}}
+
+	assert.NoError(t, mapper.Init())
+	fields := calculateProcessedValues(mapper, createTestMetric())
+
+	assertFieldValue(t, "te", "string_value", fields)
+}
+
+func TestInvalidRegex(t *testing.T) {
+	mapper := EnumMapper{Mappings: []Mapping{{
+		Field: "string_value",
+		RegexMappings: []RegexMapping{
+			{Pattern: "(", Value: "invalid"},
+			{Pattern: "test", Value: "valid"},
+		},
+	}}}
+
+	assert.Error(t, mapper.Init())
+}
+
+func TestWritesFieldToTag(t *testing.T) {
+	mapper := EnumMapper{Mappings: []Mapping{{
+		Field:         "int_value",
+		Dest:          "status",
+		DestType:      "tag",
+		RangeMappings: []RangeMapping{{Min: floatPtr(10), Value: int64(2)}},
+	}}}
+	target := mapper.Apply(createTestMetric())[0]
+
+	assertFieldValue(t, 13, "int_value", target.Fields())
+	assertTagValue(t, "2", "status", target.Tags())
+}
+
+func TestWritesTagToField(t *testing.T) {
+	mapper := EnumMapper{Mappings: []Mapping{{
+		Tag:           "tag",
+		DestType:      "field",
+		ValueMappings: map[string]interface{}{"tag_value": int64(1)},
+	}}}
+	target := mapper.Apply(createTestMetric())[0]
+
+	assertTagValue(t, "tag_value", "tag", target.Tags())
+	assertFieldValue(t, 1, "tag", target.Fields())
+}
+
+func TestInvalidDestType(t *testing.T) {
+	mapper := EnumMapper{Mappings: []Mapping{{Field: "string_value", DestType: "name"}}}
+
+	assert.Error(t, mapper.Init())
+}
@@ -0,0 +1,152 @@
+package execd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/internal/process"
+	"github.com/influxdata/telegraf/plugins/parsers"
+	"github.com/influxdata/telegraf/plugins/processors"
+	"github.com/influxdata/telegraf/plugins/serializers"
+)
+
+const sampleConfig = `
+  ## Program to run as daemon, the first element is the program and the
+  ## remaining elements are its arguments.
+  ## Metrics are written to the standard input of the program, and the
+  ## transformed metrics are read back from its standard output.
+  command = ["python", "transform.py"]
+
+  ## Delay before the process is restarted after an unexpected termination.
+  ## The delay is doubled after each consecutive restart up to
+  ## max_restart_delay, and is reset once the process has run for longer
+  ## than max_restart_delay.
+  restart_delay = "10s"
+  max_restart_delay = "5m"
+
+  ## Data format used both to write metrics to the program and to read them
+  ## back.  Each data format has its own unique set of configuration options,
+  ## read more about them here:
+  ## https://github.com/influxdata/telegraf/blob/master/docs/DATA_FORMATS_INPUT.md
+  ## https://github.com/influxdata/telegraf/blob/master/docs/DATA_FORMATS_OUTPUT.md
+  data_format = "influx"
+`
+
+type Execd struct {
+	Command         []string          `toml:"command"`
+	RestartDelay    internal.Duration `toml:"restart_delay"`
+	MaxRestartDelay internal.Duration `toml:"max_restart_delay"`
+
+	acc        telegraf.Accumulator
+	parser     parsers.Parser
+	serializer serializers.Serializer
+	process    *process.Process
+}
+
+func NewExecd() *Execd {
+	return &Execd{
+		RestartDelay:    internal.Duration{Duration: 10 * time.Second},
+		MaxRestartDelay: internal.Duration{Duration: 5 * time.Minute},
+	}
+}
+
+func (e *Execd) SampleConfig() string {
+	return sampleConfig
+}
+
+func (e *Execd) Description() string {
+	return "Run executable as long-running processor plugin"
+}
+
+func (e *Execd) SetParser(parser parsers.Parser) {
+	e.parser = parser
+}
+
+func (e *Execd) SetSerializer(serializer serializers.Serializer) {
+	e.serializer = serializer
+}
+
+func (e *Execd) Start(acc telegraf.Accumulator) error {
+	e.acc = acc
+
+	var err error
+	e.process, err = process.New("processors.execd", e.Command)
+	if err != nil {
+		return err
+	}
+	e.process.ReadStdoutFn = e.cmdReadOut
+	e.process.ReadStderrFn = e.cmdReadErr
+	e.process.RestartDelay = e.RestartDelay.Duration
+	e.process.MaxRestartDelay = e.MaxRestartDelay.Duration
+	e.process.StopTimeout = process.DefaultStopTimeout
+
+	return e.process.Start()
+}
+
+func (e *Execd) Add(metric telegraf.Metric, acc telegraf.Accumulator) error {
+	b, err := e.serializer.Serialize(metric)
+	if err != nil {
+		return fmt.Errorf("error serializing metric: %s", err)
+	}
+
+	if _, err := e.process.Write(b); err != nil {
+		metric.Reject()
+		acc.AddError(fmt.Errorf("error writing to stdin: %s", err))
+		return nil
+	}
+
+	// The program may drop, split or combine metrics, so the metrics read
+	// back cannot be related to the metrics written.  Once written the metric
+	// is considered delivered and the metrics read back are new metrics.
+	metric.Accept()
+	return nil
+}
+
+func (e *Execd) Stop() error {
+	e.process.Stop()
+	return nil
+}
+
+// cmdReadOut parses each line of the process output and emits the metrics
+// read.
+func (e *Execd) cmdReadOut(out io.Reader) {
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		metrics, err := e.parser.Parse(scanner.Bytes())
+		if err != nil {
+			e.acc.AddError(fmt.Errorf("parse error: %s", err))
+			continue
+		}
+
+		for _, metric := range metrics {
+			e.acc.AddMetric(metric)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		e.acc.AddError(fmt.Errorf("error reading stdout: %s", err))
+	}
+}
+
+// cmdReadErr forwards each line of the process error output to the log.
+func (e *Execd) cmdReadErr(out io.Reader) {
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		log.Printf("E! [processors.execd] stderr: %q", scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		e.acc.AddError(fmt.Errorf("error reading stderr: %s", err))
+	}
+}
+
+func init() {
+	processors.AddStreaming("execd", func() telegraf.StreamingProcessor {
+		return NewExecd()
+	})
+}
@@ -0,0 +1,212 @@
+// +build !windows
+
+package execd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/parsers"
+	"github.com/influxdata/telegraf/plugins/serializers"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// metricAccumulator keeps the metrics added so their tracking can be checked.
+type metricAccumulator struct {
+	testutil.Accumulator
+	metrics chan telegraf.Metric
+}
+
+func (a *metricAccumulator) AddMetric(m telegraf.Metric) {
+	a.metrics <- m
+}
+
+func newTestExecd(t *testing.T, script string) *Execd {
+	parser, err := parsers.NewInfluxParser()
+	require.NoError(t, err)
+	serializer, err := serializers.NewInfluxSerializer()
+	require.NoError(t, err)
+
+	e := NewExecd()
+	e.Command = []string{"sh", "-c", script}
+	e.RestartDelay = internal.Duration{Duration: time.Hour}
+	e.MaxRestartDelay = internal.Duration{Duration: time.Hour}
+	e.SetParser(parser)
+	e.SetSerializer(serializer)
+	return e
+}
+
+func TestExecd(t *testing.T) {
+	e := newTestExecd(t, `while read -r line; do echo "transformed_$line"; done`)
+
+	acc := &metricAccumulator{metrics: make(chan telegraf.Metric, 10)}
+	require.NoError(t, e.Start(acc))
+	defer e.Stop()
+
+	m := testutil.MustMetric(
+		"cpu",
+		map[string]string{
+			"host": "a",
+		},
+		map[string]interface{}{
+			"usage_idle": 42,
+		},
+		time.Unix(0, 0),
+	)
+	require.NoError(t, e.Add(m, acc))
+
+	expected := testutil.MustMetric(
+		"transformed_cpu",
+		map[string]string{
+			"host": "a",
+		},
+		map[string]interface{}{
+			"usage_idle": 42,
+		},
+		time.Unix(0, 0),
+	)
+	testutil.RequireMetricEqual(t, expected, <-acc.metrics)
+}
+
+func TestExecdTracking(t *testing.T) {
+	e := newTestExecd(t, `while read -r line; do echo "cpu,host=b value=1i"; done`)
+
+	acc := &metricAccumulator{metrics: make(chan telegraf.Metric, 10)}
+	require.NoError(t, e.Start(acc))
+	defer e.Stop()
+
+	delivered := make(chan telegraf.DeliveryInfo, 1)
+	m, _ := metric.WithTracking(
+		testutil.MustMetric(
+			"cpu",
+			map[string]string{
+				"host": "a",
+			},
+			map[string]interface{}{
+				"value": 42,
+			},
+			time.Unix(0, 0),
+		),
+		func(di telegraf.DeliveryInfo) {
+			delivered <- di
+		},
+	)
+	require.NoError(t, e.Add(m, acc))
+
+	// The metric is delivered once written to the program.
+	require.True(t, (<-delivered).Delivered())
+
+	out := <-acc.metrics
+	require.Equal(t, "b", out.Tags()["host"])
+}
+
+func TestExecdDropsMetric(t *testing.T) {
+	e := newTestExecd(t, `while read -r line; do :; done`)
+
+	acc := &metricAccumulator{metrics: make(chan telegraf.Metric, 10)}
+	require.NoError(t, e.Start(acc))
+
+	delivered := make(chan telegraf.DeliveryInfo, 1)
+	m, _ := metric.WithTracking(
+		testutil.MustMetric(
+			"cpu",
+			map[string]string{},
+			map[string]interface{}{
+				"value": 42,
+			},
+			time.Unix(0, 0),
+		),
+		func(di telegraf.DeliveryInfo) {
+			delivered <- di
+		},
+	)
+	require.NoError(t, e.Add(m, acc))
+	require.NoError(t, e.Stop())
+
+	require.True(t, (<-delivered).Delivered())
+	require.Len(t, acc.metrics, 0)
+}
+
+func TestExecdSplitsMetric(t *testing.T) {
+	e := newTestExecd(t, `while read -r line; do echo "first_$line"; echo "second_$line"; done`)
+
+	acc := &metricAccumulator{metrics: make(chan telegraf.Metric, 10)}
+	require.NoError(t, e.Start(acc))
+
+	delivered := make(chan telegraf.DeliveryInfo, 1)
+	m, _ := metric.WithTracking(
+		testutil.MustMetric(
+			"cpu",
+			map[string]string{},
+			map[string]interface{}{
+				"value": 42,
+			},
+			time.Unix(0, 0),
+		),
+		func(di telegraf.DeliveryInfo) {
+			delivered <- di
+		},
+	)
+	require.NoError(t, e.Add(m, acc))
+	require.NoError(t, e.Stop())
+
+	require.True(t, (<-delivered).Delivered())
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"first_cpu",
+			map[string]string{},
+			map[string]interface{}{
+				"value": 42,
+			},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric(
+			"second_cpu",
+			map[string]string{},
+			map[string]interface{}{
+				"value": 42,
+			},
+			time.Unix(0, 0),
+		),
+	}
+	require.Len(t, acc.metrics, 2)
+	testutil.RequireMetricsEqual(t, expected, []telegraf.Metric{<-acc.metrics, <-acc.metrics})
+}
+
+func TestExecdStopEmitsInFlight(t *testing.T) {
+	e := newTestExecd(t, `while read -r line; do sleep 0.5; echo "$line"; done`)
+
+	acc := &metricAccumulator{metrics: make(chan telegraf.Metric, 10)}
+	require.NoError(t, e.Start(acc))
+
+	m := testutil.MustMetric(
+		"cpu",
+		map[string]string{},
+		map[string]interface{}{
+			"value": 42,
+		},
+		time.Unix(0, 0),
+	)
+	require.NoError(t, e.Add(m.Copy(), acc))
+	require.NoError(t, e.Stop())
+
+	// The metric must have been emitted by the time Stop returns.
+	select {
+	case out := <-acc.metrics:
+		testutil.RequireMetricEqual(t, m, out)
+	default:
+		t.Fatal("metric was not emitted before Stop returned")
+	}
+}
+
+func TestExecdNoCommand(t *testing.T) {
+	e := NewExecd()
+
+	acc := &metricAccumulator{metrics: make(chan telegraf.Metric, 10)}
+	require.Error(t, e.Start(acc))
+}
@@ -0,0 +1,55 @@
+package geoip
+
+import (
+	"container/list"
+)
+
+// cache keeps the records of the most recently used addresses.
+type cache struct {
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	addr   string
+	record *record
+}
+
+func newCache(size int) *cache {
+	return &cache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// get returns the record of the address, the record is nil if the address
+// was not found in the databases.
+func (c *cache) get(addr string) (*record, bool) {
+	elem, ok := c.entries[addr]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).record, true
+}
+
+func (c *cache) set(addr string, r *record) {
+	if c.size <= 0 {
+		return
+	}
+
+	if elem, ok := c.entries[addr]; ok {
+		elem.Value.(*cacheEntry).record = r
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).addr)
+	}
+	c.entries[addr] = c.order.PushFront(&cacheEntry{addr: addr, record: r})
+}
@@ -0,0 +1,252 @@
+package geoip
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/processors"
+	"github.com/oschwald/maxminddb-golang"
+)
+
+const sampleConfig = `
+  ## MaxMind databases in the MMDB format, such as the GeoLite2 City and ASN
+  ## databases.  The attributes of all databases are combined.
+  databases = ["/var/lib/GeoIP/GeoLite2-City.mmdb"]
+
+  ## Interval at which the databases are checked for changes, and reloaded
+  ## if modified.  Set to "0s" to disable reloading.
+  # reload_interval = "1m"
+
+  ## Number of addresses whose attributes are cached.
+  # cache_size = 1000
+
+  ## Language of the names of places.
+  # language = "en"
+
+  ## Attributes to add as tags and as fields, available attributes are:
+  ##   city_name, continent_code, continent_name, country_code, country_name,
+  ##   latitude, longitude, time_zone, postal_code, subdivision_code,
+  ##   subdivision_name, asn, as_org
+  tags = ["country_code", "city_name"]
+  fields = ["latitude", "longitude"]
+
+  [[processors.geoip.lookup]]
+    ## Tag or field containing the IP address to look up, only one of tag or
+    ## field can be set.
+    tag = "client_ip"
+    # field = "client_ip"
+
+    ## Prefix of the names of the tags and fields added.
+    prefix = "geoip_"
+`
+
+type GeoIP struct {
+	Databases      []string          `toml:"databases"`
+	ReloadInterval internal.Duration `toml:"reload_interval"`
+	CacheSize      int               `toml:"cache_size"`
+	Language       string            `toml:"language"`
+	Tags           []string          `toml:"tags"`
+	Fields         []string          `toml:"fields"`
+	Lookups        []lookup          `toml:"lookup"`
+
+	readers   []*maxminddb.Reader
+	modTimes  map[string]time.Time
+	lastCheck time.Time
+	cache     *cache
+	now       func() time.Time
+}
+
+type lookup struct {
+	Tag    string `toml:"tag"`
+	Field  string `toml:"field"`
+	Prefix string `toml:"prefix"`
+}
+
+func NewGeoIP() *GeoIP {
+	return &GeoIP{
+		ReloadInterval: internal.Duration{Duration: time.Minute},
+		CacheSize:      1000,
+		Language:       "en",
+		now:            time.Now,
+	}
+}
+
+func (g *GeoIP) SampleConfig() string {
+	return sampleConfig
+}
+
+func (g *GeoIP) Description() string {
+	return "Add the location of IP addresses using MaxMind databases"
+}
+
+func (g *GeoIP) Init() error {
+	if len(g.Databases) == 0 {
+		return errors.New("databases must be set")
+	}
+
+	for _, names := range [][]string{g.Tags, g.Fields} {
+		for _, name := range names {
+			if !attributes[name] {
+				return fmt.Errorf("unknown attribute: %s", name)
+			}
+		}
+	}
+
+	for _, l := range g.Lookups {
+		if (l.Tag == "") == (l.Field == "") {
+			return errors.New("exactly one of tag or field must be set for each lookup")
+		}
+	}
+
+	g.cache = newCache(g.CacheSize)
+	g.reload()
+	return nil
+}
+
+func (g *GeoIP) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	if g.ReloadInterval.Duration > 0 && g.now().Sub(g.lastCheck) >= g.ReloadInterval.Duration {
+		g.reload()
+	}
+
+	for _, m := range in {
+		for _, l := range g.Lookups {
+			g.enrich(m, l)
+		}
+	}
+	return in
+}
+
+// Close closes the databases.
+func (g *GeoIP) Close() error {
+	closeReaders(g.readers)
+	g.readers = nil
+	return nil
+}
+
+// reload opens the databases if any of them changed since they were last
+// opened.  If a database cannot be opened the previous databases are kept.
+func (g *GeoIP) reload() {
+	g.lastCheck = g.now()
+
+	modTimes := make(map[string]time.Time, len(g.Databases))
+	changed := g.readers == nil
+	for _, path := range g.Databases {
+		info, err := os.Stat(path)
+		if err != nil {
+			log.Printf("E! [processors.geoip] Could not open databases: %v", err)
+			return
+		}
+		modTimes[path] = info.ModTime()
+		if !info.ModTime().Equal(g.modTimes[path]) {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return
+	}
+
+	readers := make([]*maxminddb.Reader, 0, len(g.Databases))
+	for _, path := range g.Databases {
+		reader, err := maxminddb.Open(path)
+		if err != nil {
+			log.Printf("E! [processors.geoip] Could not open database %s: %v", path, err)
+			closeReaders(readers)
+			return
+		}
+		readers = append(readers, reader)
+	}
+
+	if g.readers != nil {
+		log.Printf("I! [processors.geoip] Reloaded databases")
+	}
+	closeReaders(g.readers)
+	g.readers = readers
+	g.modTimes = modTimes
+	g.cache = newCache(g.CacheSize)
+}
+
+// lookup returns the combined record of the address in all databases, nil is
+// returned if the address is not found.
+func (g *GeoIP) lookup(ip net.IP) *record {
+	addr := ip.String()
+	if r, ok := g.cache.get(addr); ok {
+		return r
+	}
+
+	var found bool
+	r := &record{}
+	for _, reader := range g.readers {
+		_, ok, err := reader.LookupNetwork(ip, r)
+		if err != nil {
+			log.Printf("E! [processors.geoip] Could not look up %s: %v", addr, err)
+			continue
+		}
+		found = found || ok
+	}
+	if !found {
+		r = nil
+	}
+
+	g.cache.set(addr, r)
+	return r
+}
+
+func (g *GeoIP) enrich(m telegraf.Metric, l lookup) {
+	var value string
+	if l.Tag != "" {
+		tag, ok := m.GetTag(l.Tag)
+		if !ok {
+			return
+		}
+		value = tag
+	} else {
+		field, ok := m.GetField(l.Field)
+		if !ok {
+			return
+		}
+		value, ok = field.(string)
+		if !ok {
+			return
+		}
+	}
+
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return
+	}
+
+	r := g.lookup(ip)
+	if r == nil {
+		return
+	}
+
+	for _, name := range g.Tags {
+		if value, ok := r.attribute(name, g.Language); ok {
+			m.AddTag(l.Prefix+name, asTag(value))
+		}
+	}
+	for _, name := range g.Fields {
+		if value, ok := r.attribute(name, g.Language); ok {
+			m.AddField(l.Prefix+name, value)
+		}
+	}
+}
+
+func closeReaders(readers []*maxminddb.Reader) {
+	for _, reader := range readers {
+		reader.Close()
+	}
+}
+
+func init() {
+	processors.Add("geoip", func() telegraf.Processor {
+		return NewGeoIP()
+	})
+}
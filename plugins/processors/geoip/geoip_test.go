@@ -0,0 +1,223 @@
+package geoip
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/processors"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	plugin := NewGeoIP()
+	plugin.Databases = []string{
+		"testdata/GeoIP2-City-Test.mmdb",
+		"testdata/GeoLite2-ASN-Test.mmdb",
+	}
+	plugin.Tags = []string{"country_code", "city_name", "subdivision_code", "asn"}
+	plugin.Fields = []string{"latitude", "longitude", "as_org"}
+	plugin.Lookups = []lookup{
+		{Tag: "client_ip", Prefix: "geoip_"},
+		{Field: "server", Prefix: "server_"},
+	}
+
+	input := []telegraf.Metric{
+		testutil.MustMetric("access",
+			map[string]string{"client_ip": "192.0.2.10"},
+			map[string]interface{}{"server": "198.51.100.1"},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("access",
+			map[string]string{"client_ip": "203.0.113.1"},
+			map[string]interface{}{"server": "invalid"},
+			time.Unix(0, 0),
+		),
+	}
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("access",
+			map[string]string{
+				"client_ip":              "192.0.2.10",
+				"geoip_country_code":     "DE",
+				"geoip_city_name":        "Berlin",
+				"geoip_subdivision_code": "BE",
+				"geoip_asn":              "64500",
+				"server_country_code":    "US",
+			},
+			map[string]interface{}{
+				"server":           "198.51.100.1",
+				"geoip_latitude":   52.5,
+				"geoip_longitude":  13.4,
+				"geoip_as_org":     "Example Networks",
+				"server_latitude":  37.75,
+				"server_longitude": -97.82,
+			},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("access",
+			map[string]string{"client_ip": "203.0.113.1"},
+			map[string]interface{}{"server": "invalid"},
+			time.Unix(0, 0),
+		),
+	}
+
+	require.NoError(t, plugin.Init())
+	actual := plugin.Apply(input...)
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestLanguage(t *testing.T) {
+	plugin := NewGeoIP()
+	plugin.Databases = []string{"testdata/GeoIP2-City-Test.mmdb"}
+	plugin.Language = "de"
+	plugin.Tags = []string{"country_name"}
+	plugin.Lookups = []lookup{{Tag: "client_ip"}}
+
+	m := testutil.MustMetric("access",
+		map[string]string{"client_ip": "192.0.2.10"},
+		map[string]interface{}{"value": 42},
+		time.Unix(0, 0),
+	)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("access",
+			map[string]string{"client_ip": "192.0.2.10", "country_name": "Deutschland"},
+			map[string]interface{}{"value": 42},
+			time.Unix(0, 0),
+		),
+	}
+
+	require.NoError(t, plugin.Init())
+	actual := plugin.Apply(m)
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "geoip")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "GeoLite2.mmdb")
+	copyFile(t, "testdata/GeoLite2-ASN-Test.mmdb", path)
+
+	now := time.Unix(0, 0)
+	plugin := NewGeoIP()
+	plugin.Databases = []string{path}
+	plugin.Tags = []string{"country_code", "asn"}
+	plugin.Lookups = []lookup{{Tag: "client_ip"}}
+	plugin.now = func() time.Time {
+		return now
+	}
+
+	newMetric := func() telegraf.Metric {
+		return testutil.MustMetric("access",
+			map[string]string{"client_ip": "192.0.2.10"},
+			map[string]interface{}{"value": 42},
+			time.Unix(0, 0),
+		)
+	}
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("access",
+			map[string]string{"client_ip": "192.0.2.10", "asn": "64500"},
+			map[string]interface{}{"value": 42},
+			time.Unix(0, 0),
+		),
+	}
+	require.NoError(t, plugin.Init())
+	testutil.RequireMetricsEqual(t, expected, plugin.Apply(newMetric()))
+
+	// The database is replaced, as done by the MaxMind updater.
+	tmp := filepath.Join(dir, "GeoLite2.mmdb.tmp")
+	copyFile(t, "testdata/GeoIP2-City-Test.mmdb", tmp)
+	require.NoError(t, os.Chtimes(tmp, time.Now(), time.Now().Add(time.Hour)))
+	require.NoError(t, os.Rename(tmp, path))
+
+	now = now.Add(time.Minute)
+	expected = []telegraf.Metric{
+		testutil.MustMetric("access",
+			map[string]string{"client_ip": "192.0.2.10", "country_code": "DE"},
+			map[string]interface{}{"value": 42},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, plugin.Apply(newMetric()))
+}
+
+func TestStopClosesDatabases(t *testing.T) {
+	processor := processors.Processors["geoip"]()
+	plugin := processor.(processors.Unwrapper).Unwrap().(*GeoIP)
+	plugin.Databases = []string{"testdata/GeoLite2-ASN-Test.mmdb"}
+	plugin.Tags = []string{"asn"}
+	plugin.Lookups = []lookup{{Tag: "client_ip"}}
+
+	require.NoError(t, processor.Start(&testutil.Accumulator{}))
+	require.Len(t, plugin.readers, 1)
+
+	require.NoError(t, processor.Stop())
+	require.Empty(t, plugin.readers)
+}
+
+func TestCache(t *testing.T) {
+	c := newCache(2)
+	a, b := &record{ASOrg: "a"}, &record{ASOrg: "b"}
+
+	c.set("192.0.2.1", a)
+	c.set("192.0.2.2", b)
+	r, ok := c.get("192.0.2.1")
+	require.True(t, ok)
+	require.Equal(t, a, r)
+
+	// The least recently used address is evicted.
+	c.set("192.0.2.3", nil)
+	_, ok = c.get("192.0.2.2")
+	require.False(t, ok)
+	_, ok = c.get("192.0.2.1")
+	require.True(t, ok)
+	r, ok = c.get("192.0.2.3")
+	require.True(t, ok)
+	require.Nil(t, r)
+}
+
+func TestInvalidConfiguration(t *testing.T) {
+	var tests = []struct {
+		name   string
+		plugin *GeoIP
+	}{
+		{
+			name:   "no databases",
+			plugin: &GeoIP{Tags: []string{"country_code"}},
+		},
+		{
+			name: "unknown attribute",
+			plugin: &GeoIP{
+				Databases: []string{"testdata/GeoIP2-City-Test.mmdb"},
+				Fields:    []string{"altitude"},
+			},
+		},
+		{
+			name: "tag and field",
+			plugin: &GeoIP{
+				Databases: []string{"testdata/GeoIP2-City-Test.mmdb"},
+				Lookups:   []lookup{{Tag: "ip", Field: "ip"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Error(t, tt.plugin.Init())
+		})
+	}
+}
+
+func copyFile(t *testing.T, src, dst string) {
+	data, err := ioutil.ReadFile(src)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(dst, data, 0644))
+}
@@ -0,0 +1,113 @@
+package geoip
+
+import (
+	"strconv"
+)
+
+// record is the union of the data of the GeoIP2 and GeoLite2 City, Country and
+// ASN databases.  Attributes missing from the databases are left empty.
+type record struct {
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Continent struct {
+		Code  string            `maxminddb:"code"`
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"continent"`
+	Country struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	Location struct {
+		Latitude  *float64 `maxminddb:"latitude"`
+		Longitude *float64 `maxminddb:"longitude"`
+		TimeZone  string   `maxminddb:"time_zone"`
+	} `maxminddb:"location"`
+	Postal struct {
+		Code string `maxminddb:"code"`
+	} `maxminddb:"postal"`
+	Subdivisions []struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"subdivisions"`
+	ASN   uint   `maxminddb:"autonomous_system_number"`
+	ASOrg string `maxminddb:"autonomous_system_organization"`
+}
+
+// attributes are the names of the attributes that can be added to metrics.
+var attributes = map[string]bool{
+	"city_name":        true,
+	"continent_code":   true,
+	"continent_name":   true,
+	"country_code":     true,
+	"country_name":     true,
+	"latitude":         true,
+	"longitude":        true,
+	"time_zone":        true,
+	"postal_code":      true,
+	"subdivision_code": true,
+	"subdivision_name": true,
+	"asn":              true,
+	"as_org":           true,
+}
+
+// attribute returns the value of the attribute, ok is false if the attribute
+// is not known for the address.  Names are returned in the language.
+func (r *record) attribute(name, language string) (value interface{}, ok bool) {
+	var s string
+	switch name {
+	case "city_name":
+		s = r.City.Names[language]
+	case "continent_code":
+		s = r.Continent.Code
+	case "continent_name":
+		s = r.Continent.Names[language]
+	case "country_code":
+		s = r.Country.ISOCode
+	case "country_name":
+		s = r.Country.Names[language]
+	case "latitude":
+		if r.Location.Latitude == nil {
+			return nil, false
+		}
+		return *r.Location.Latitude, true
+	case "longitude":
+		if r.Location.Longitude == nil {
+			return nil, false
+		}
+		return *r.Location.Longitude, true
+	case "time_zone":
+		s = r.Location.TimeZone
+	case "postal_code":
+		s = r.Postal.Code
+	case "subdivision_code":
+		if len(r.Subdivisions) > 0 {
+			s = r.Subdivisions[0].ISOCode
+		}
+	case "subdivision_name":
+		if len(r.Subdivisions) > 0 {
+			s = r.Subdivisions[0].Names[language]
+		}
+	case "asn":
+		if r.ASN == 0 {
+			return nil, false
+		}
+		return int64(r.ASN), true
+	case "as_org":
+		s = r.ASOrg
+	}
+	return s, s != ""
+}
+
+// asTag returns the value of an attribute as a tag value.
+func asTag(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return ""
+}
@@ -0,0 +1,181 @@
+package lookup
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+const sampleConfig = `
+  ## Lookup files, entries of later files replace entries of earlier files
+  ## with the same key.
+  files = ["/etc/telegraf/hosts.csv"]
+
+  ## Format of the lookup files, one of "csv" or "json".
+  ##   csv  : The first row is a header naming the columns.  The columns named
+  ##          after the key tags make up the key, the other columns are added
+  ##          to the metrics.  Empty cells are ignored.
+  ##   json : An object mapping each key to an object of the values to add to
+  ##          the metrics.
+  format = "csv"
+
+  ## Tags whose values are the key of the lookup.  Metrics missing any of the
+  ## tags are not modified.
+  key_tags = ["host"]
+
+  ## Separator joining the values of multiple key tags into the keys of JSON
+  ## lookup files.
+  # key_separator = ":"
+
+  ## Values added as fields, all other values are added as tags.
+  # fields = []
+
+  ## Interval at which the files are checked for changes, and reloaded if
+  ## modified.  Set to "0s" to disable reloading.
+  # reload_interval = "1m"
+`
+
+type Lookup struct {
+	Files          []string          `toml:"files"`
+	Format         string            `toml:"format"`
+	KeyTags        []string          `toml:"key_tags"`
+	KeySeparator   string            `toml:"key_separator"`
+	Fields         []string          `toml:"fields"`
+	ReloadInterval internal.Duration `toml:"reload_interval"`
+
+	isField   map[string]bool
+	table     table
+	modTimes  map[string]time.Time
+	lastCheck time.Time
+	now       func() time.Time
+}
+
+func NewLookup() *Lookup {
+	return &Lookup{
+		Format:         "csv",
+		KeySeparator:   ":",
+		ReloadInterval: internal.Duration{Duration: time.Minute},
+		now:            time.Now,
+	}
+}
+
+func (l *Lookup) SampleConfig() string {
+	return sampleConfig
+}
+
+func (l *Lookup) Description() string {
+	return "Add tags and fields to metrics from lookup files"
+}
+
+func (l *Lookup) Init() error {
+	if len(l.Files) == 0 {
+		return errors.New("files must be set")
+	}
+
+	switch l.Format {
+	case "csv", "json":
+	default:
+		return fmt.Errorf("invalid format: %s", l.Format)
+	}
+
+	if len(l.KeyTags) == 0 {
+		return errors.New("key_tags must be set")
+	}
+
+	l.isField = make(map[string]bool, len(l.Fields))
+	for _, field := range l.Fields {
+		l.isField[field] = true
+	}
+
+	if err := l.reload(); err != nil {
+		return fmt.Errorf("could not load lookup files: %v", err)
+	}
+	return nil
+}
+
+func (l *Lookup) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	if l.ReloadInterval.Duration > 0 && l.now().Sub(l.lastCheck) >= l.ReloadInterval.Duration {
+		if err := l.reload(); err != nil {
+			log.Printf("E! [processors.lookup] Could not load lookup files: %v", err)
+		}
+	}
+
+	for _, m := range in {
+		l.enrich(m)
+	}
+	return in
+}
+
+// reload loads the files if any of them changed since they were last loaded.
+// If a file cannot be loaded the previous entries are kept.
+func (l *Lookup) reload() error {
+	l.lastCheck = l.now()
+
+	modTimes := make(map[string]time.Time, len(l.Files))
+	changed := l.table == nil
+	for _, path := range l.Files {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		modTimes[path] = info.ModTime()
+		if !info.ModTime().Equal(l.modTimes[path]) {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	t := make(table)
+	for _, path := range l.Files {
+		if err := l.loadFile(t, path); err != nil {
+			return err
+		}
+	}
+
+	if l.table != nil {
+		log.Printf("I! [processors.lookup] Reloaded lookup files")
+	}
+	l.table = t
+	l.modTimes = modTimes
+	return nil
+}
+
+func (l *Lookup) enrich(m telegraf.Metric) {
+	values := make([]string, 0, len(l.KeyTags))
+	for _, key := range l.KeyTags {
+		value, ok := m.GetTag(key)
+		if !ok {
+			return
+		}
+		values = append(values, value)
+	}
+
+	e, ok := l.table[strings.Join(values, l.KeySeparator)]
+	if !ok {
+		return
+	}
+
+	for name, value := range e {
+		if l.isField[name] {
+			m.AddField(name, value)
+		} else {
+			m.AddTag(name, fmt.Sprint(value))
+		}
+	}
+}
+
+func init() {
+	processors.Add("lookup", func() telegraf.Processor {
+		return NewLookup()
+	})
+}
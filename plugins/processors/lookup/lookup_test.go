@@ -0,0 +1,205 @@
+package lookup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupCSV(t *testing.T) {
+	plugin := NewLookup()
+	plugin.Files = []string{"testdata/hosts.csv"}
+	plugin.KeyTags = []string{"host"}
+
+	input := []telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "web01"},
+			map[string]interface{}{"usage_idle": 42.0},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "db01"},
+			map[string]interface{}{"usage_idle": 42.0},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "unknown"},
+			map[string]interface{}{"usage_idle": 42.0},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{"usage_idle": 42.0},
+			time.Unix(0, 0),
+		),
+	}
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "web01", "owner": "alice", "team": "frontend", "rack": "r1"},
+			map[string]interface{}{"usage_idle": 42.0},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "db01", "owner": "bob", "team": "storage"},
+			map[string]interface{}{"usage_idle": 42.0},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "unknown"},
+			map[string]interface{}{"usage_idle": 42.0},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{"usage_idle": 42.0},
+			time.Unix(0, 0),
+		),
+	}
+
+	require.NoError(t, plugin.Init())
+	actual := plugin.Apply(input...)
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestLookupJSONMultipleKeyTags(t *testing.T) {
+	plugin := NewLookup()
+	plugin.Files = []string{"testdata/services.json"}
+	plugin.Format = "json"
+	plugin.KeyTags = []string{"host", "port"}
+	plugin.Fields = []string{"weight", "critical"}
+
+	input := []telegraf.Metric{
+		testutil.MustMetric("net_response",
+			map[string]string{"host": "web01", "port": "80"},
+			map[string]interface{}{"response_time": 0.1},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("net_response",
+			map[string]string{"host": "db01", "port": "5432"},
+			map[string]interface{}{"response_time": 0.1},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("net_response",
+			map[string]string{"host": "db01", "port": "80"},
+			map[string]interface{}{"response_time": 0.1},
+			time.Unix(0, 0),
+		),
+	}
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("net_response",
+			map[string]string{"host": "web01", "port": "80", "service": "nginx"},
+			map[string]interface{}{"response_time": 0.1, "weight": int64(10)},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("net_response",
+			map[string]string{"host": "db01", "port": "5432", "service": "postgres"},
+			map[string]interface{}{"response_time": 0.1, "weight": 2.5, "critical": true},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("net_response",
+			map[string]string{"host": "db01", "port": "80"},
+			map[string]interface{}{"response_time": 0.1},
+			time.Unix(0, 0),
+		),
+	}
+
+	require.NoError(t, plugin.Init())
+	actual := plugin.Apply(input...)
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lookup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "hosts.csv")
+	require.NoError(t, ioutil.WriteFile(path, []byte("host,team\nweb01,frontend\n"), 0644))
+
+	now := time.Unix(0, 0)
+	plugin := NewLookup()
+	plugin.Files = []string{path}
+	plugin.KeyTags = []string{"host"}
+	plugin.ReloadInterval = internal.Duration{Duration: time.Minute}
+	plugin.now = func() time.Time {
+		return now
+	}
+
+	newMetric := func() telegraf.Metric {
+		return testutil.MustMetric("cpu",
+			map[string]string{"host": "web01"},
+			map[string]interface{}{"usage_idle": 42.0},
+			time.Unix(0, 0),
+		)
+	}
+	expected := func(team string) []telegraf.Metric {
+		return []telegraf.Metric{
+			testutil.MustMetric("cpu",
+				map[string]string{"host": "web01", "team": team},
+				map[string]interface{}{"usage_idle": 42.0},
+				time.Unix(0, 0),
+			),
+		}
+	}
+
+	require.NoError(t, plugin.Init())
+	testutil.RequireMetricsEqual(t, expected("frontend"), plugin.Apply(newMetric()))
+
+	// The file is not checked again before the reload interval.
+	require.NoError(t, ioutil.WriteFile(path, []byte("host,team\nweb01,backend\n"), 0644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Hour)))
+	now = now.Add(30 * time.Second)
+	testutil.RequireMetricsEqual(t, expected("frontend"), plugin.Apply(newMetric()))
+
+	now = now.Add(30 * time.Second)
+	testutil.RequireMetricsEqual(t, expected("backend"), plugin.Apply(newMetric()))
+
+	// An invalid file keeps the previous entries.
+	require.NoError(t, ioutil.WriteFile(path, []byte("team\nweb01\n"), 0644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(2*time.Hour)))
+	now = now.Add(time.Minute)
+	testutil.RequireMetricsEqual(t, expected("backend"), plugin.Apply(newMetric()))
+}
+
+func TestInvalidConfiguration(t *testing.T) {
+	var tests = []struct {
+		name   string
+		plugin *Lookup
+	}{
+		{
+			name:   "no files",
+			plugin: &Lookup{Format: "csv", KeyTags: []string{"host"}},
+		},
+		{
+			name:   "invalid format",
+			plugin: &Lookup{Files: []string{"testdata/hosts.csv"}, Format: "yaml", KeyTags: []string{"host"}},
+		},
+		{
+			name:   "no key tags",
+			plugin: &Lookup{Files: []string{"testdata/hosts.csv"}, Format: "csv"},
+		},
+		{
+			name:   "missing file",
+			plugin: &Lookup{Files: []string{"testdata/missing.csv"}, Format: "csv", KeyTags: []string{"host"}, now: time.Now},
+		},
+		{
+			name:   "missing key column",
+			plugin: &Lookup{Files: []string{"testdata/hosts.csv"}, Format: "csv", KeyTags: []string{"datacenter"}, now: time.Now},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Error(t, tt.plugin.Init())
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package lookup
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// entry is the tags and fields added to metrics matching a key.
+type entry map[string]interface{}
+
+// table maps the keys built from the tags of a metric to entries.
+type table map[string]entry
+
+// loadFile adds the entries of the file to the table, entries of previous
+// files with the same key are replaced.
+func (l *Lookup) loadFile(t table, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch l.Format {
+	case "csv":
+		err = l.loadCSV(t, f)
+	case "json":
+		err = l.loadJSON(t, f)
+	}
+	if err != nil {
+		return fmt.Errorf("loading %s: %v", path, err)
+	}
+	return nil
+}
+
+// loadCSV reads a CSV file with a header row.  The columns named after the
+// key tags make up the key, the other columns are added to the metrics.
+func (l *Lookup) loadCSV(t table, r io.Reader) error {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return errors.New("missing header")
+	}
+	if err != nil {
+		return err
+	}
+
+	keyColumns := make([]int, 0, len(l.KeyTags))
+	for _, key := range l.KeyTags {
+		column := -1
+		for i, name := range header {
+			if name == key {
+				column = i
+				break
+			}
+		}
+		if column < 0 {
+			return fmt.Errorf("missing column for key tag %q", key)
+		}
+		keyColumns = append(keyColumns, column)
+	}
+
+	isKey := make(map[int]bool, len(keyColumns))
+	for _, column := range keyColumns {
+		isKey[column] = true
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		values := make([]string, 0, len(keyColumns))
+		for _, column := range keyColumns {
+			values = append(values, record[column])
+		}
+
+		e := make(entry, len(header)-len(keyColumns))
+		for i, name := range header {
+			// Empty cells are not added, so rows may leave out columns.
+			if isKey[i] || record[i] == "" {
+				continue
+			}
+			e[name] = record[i]
+		}
+		t[strings.Join(values, l.KeySeparator)] = e
+	}
+}
+
+// loadJSON reads a JSON object mapping keys to objects of the values to add
+// to the metrics.  Keys of multiple tags are the values of the tags joined by
+// the key separator.
+func (l *Lookup) loadJSON(t table, r io.Reader) error {
+	var entries map[string]map[string]interface{}
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	if err := decoder.Decode(&entries); err != nil {
+		return err
+	}
+
+	for key, values := range entries {
+		e := make(entry, len(values))
+		for name, value := range values {
+			switch value := value.(type) {
+			case string, bool:
+				e[name] = value
+			case json.Number:
+				if n, err := value.Int64(); err == nil {
+					e[name] = n
+				} else if f, err := value.Float64(); err == nil {
+					e[name] = f
+				} else {
+					return fmt.Errorf("invalid number for %q of key %q: %v", name, key, err)
+				}
+			case nil:
+			default:
+				return fmt.Errorf("invalid value for %q of key %q: %T", name, key, value)
+			}
+		}
+		t[key] = e
+	}
+	return nil
+}
@@ -1,7 +1,11 @@
 package override
 
 import (
+	"fmt"
+	"strconv"
+
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
 	"github.com/influxdata/telegraf/plugins/processors"
 )
 
@@ -11,9 +15,30 @@ var sampleConfig = `
   # name_prefix = "new_name_prefix"
   # name_suffix = "new_name_suffix"
 
+  ## Tags and fields to be removed, globs are supported.  Metrics left without
+  ## fields are dropped.
+  # remove_tags = []
+  # remove_fields = []
+
   ## Tags to be added (all values must be strings)
   # [processors.override.tags]
   #   additional_tag = "tag_value"
+
+  ## Conditions to be met for the modifications to be applied, by default all
+  ## metrics are modified.  All conditions must be met.
+  # [[processors.override.condition]]
+  #   ## Name of the field or tag to check
+  #   field = "usage"
+  #   # tag = "cpu"
+  #
+  #   ## Comparison with value, one of "==", "!=", ">", ">=", "<", "<=",
+  #   ## "exists" or "not_exists".  Defaults to "==" if a value is given or
+  #   ## "exists" otherwise.
+  #   operator = ">"
+  #
+  #   ## Value to compare to, numbers are compared numerically with numeric
+  #   ## fields and with tags holding a number.
+  #   value = 90
 `
 
 type Override struct {
@@ -21,6 +46,19 @@ type Override struct {
 	NamePrefix   string
 	NameSuffix   string
 	Tags         map[string]string
+	RemoveTags   []string     `toml:"remove_tags"`
+	RemoveFields []string     `toml:"remove_fields"`
+	Conditions   []*condition `toml:"condition"`
+
+	tagFilter   filter.Filter
+	fieldFilter filter.Filter
+}
+
+type condition struct {
+	Field    string
+	Tag      string
+	Operator string
+	Value    interface{}
 }
 
 func (p *Override) SampleConfig() string {
@@ -31,8 +69,50 @@ func (p *Override) Description() string {
 	return "Apply metric modifications using override semantics."
 }
 
+func (p *Override) Init() error {
+	for _, c := range p.Conditions {
+		if (c.Field == "") == (c.Tag == "") {
+			return fmt.Errorf("condition requires either field or tag")
+		}
+
+		if c.Operator == "" {
+			c.Operator = "exists"
+			if c.Value != nil {
+				c.Operator = "=="
+			}
+		}
+
+		switch c.Operator {
+		case "exists", "not_exists":
+		case "==", "!=", ">", ">=", "<", "<=":
+			if c.Value == nil {
+				return fmt.Errorf("operator %q requires a value", c.Operator)
+			}
+		default:
+			return fmt.Errorf("invalid operator: %s", c.Operator)
+		}
+	}
+
+	var err error
+	p.tagFilter, err = filter.Compile(p.RemoveTags)
+	if err != nil {
+		return fmt.Errorf("could not compile remove_tags: %v", err)
+	}
+	p.fieldFilter, err = filter.Compile(p.RemoveFields)
+	if err != nil {
+		return fmt.Errorf("could not compile remove_fields: %v", err)
+	}
+	return nil
+}
+
 func (p *Override) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	out := in[:0]
 	for _, metric := range in {
+		if !p.matches(metric) {
+			out = append(out, metric)
+			continue
+		}
+
 		if len(p.NameOverride) > 0 {
 			metric.SetName(p.NameOverride)
 		}
@@ -45,8 +125,117 @@ func (p *Override) Apply(in ...telegraf.Metric) []telegraf.Metric {
 		for key, value := range p.Tags {
 			metric.AddTag(key, value)
 		}
+
+		p.remove(metric)
+		if len(metric.FieldList()) == 0 {
+			metric.Drop()
+			continue
+		}
+
+		out = append(out, metric)
+	}
+	return out
+}
+
+// remove deletes the tags and fields matching remove_tags and remove_fields.
+func (p *Override) remove(metric telegraf.Metric) {
+	if p.tagFilter != nil {
+		keys := []string{}
+		for _, tag := range metric.TagList() {
+			if p.tagFilter.Match(tag.Key) {
+				keys = append(keys, tag.Key)
+			}
+		}
+		for _, key := range keys {
+			metric.RemoveTag(key)
+		}
+	}
+
+	if p.fieldFilter != nil {
+		keys := []string{}
+		for _, field := range metric.FieldList() {
+			if p.fieldFilter.Match(field.Key) {
+				keys = append(keys, field.Key)
+			}
+		}
+		for _, key := range keys {
+			metric.RemoveField(key)
+		}
+	}
+}
+
+// matches returns true if the metric meets all the conditions.
+func (p *Override) matches(metric telegraf.Metric) bool {
+	for _, c := range p.Conditions {
+		if !c.matches(metric) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *condition) matches(metric telegraf.Metric) bool {
+	var value interface{}
+	var ok bool
+	if c.Field != "" {
+		value, ok = metric.GetField(c.Field)
+	} else {
+		value, ok = metric.GetTag(c.Tag)
+	}
+
+	switch c.Operator {
+	case "exists":
+		return ok
+	case "not_exists":
+		return !ok
+	}
+	if !ok {
+		return false
+	}
+
+	// Compare numerically if both sides are numbers, as strings otherwise.
+	if expected, isNumber := toFloat(c.Value); isNumber {
+		if actual, isNumber := toFloat(value); isNumber {
+			return compare(c.Operator, actual < expected, actual == expected)
+		}
+	}
+
+	actual := fmt.Sprintf("%v", value)
+	expected := fmt.Sprintf("%v", c.Value)
+	return compare(c.Operator, actual < expected, actual == expected)
+}
+
+func compare(operator string, less, equal bool) bool {
+	switch operator {
+	case "==":
+		return equal
+	case "!=":
+		return !equal
+	case ">":
+		return !less && !equal
+	case ">=":
+		return !less
+	case "<":
+		return less
+	case "<=":
+		return less || equal
+	}
+	return false
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
 	}
-	return in
+	return 0, false
 }
 
 func init() {
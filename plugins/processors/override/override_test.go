@@ -78,3 +78,113 @@ func TestNameSuffix(t *testing.T) {
 
 	assert.Equal(t, "m1-suff", processed[0].Name(), "Suffix was not applied")
 }
+
+func createUsageMetric(usage float64) telegraf.Metric {
+	metric, _ := metric.New("cpu",
+		map[string]string{"cpu": "cpu0", "host": "localhost"},
+		map[string]interface{}{"usage": usage, "idle": 100 - usage},
+		time.Now(),
+	)
+	return metric
+}
+
+func TestConditionOnFieldValue(t *testing.T) {
+	processor := Override{
+		Tags:       map[string]string{"critical": "true"},
+		Conditions: []*condition{{Field: "usage", Operator: ">", Value: int64(90)}},
+	}
+
+	assert.NoError(t, processor.Init())
+	processed := processor.Apply(createUsageMetric(95), createUsageMetric(50))
+
+	assert.Equal(t, 2, len(processed))
+	assert.True(t, processed[0].HasTag("critical"), "Tag was not added to matching metric")
+	assert.False(t, processed[1].HasTag("critical"), "Tag was added to non matching metric")
+}
+
+func TestConditionOperators(t *testing.T) {
+	tests := []struct {
+		operator string
+		value    interface{}
+		expected bool
+	}{
+		{"==", float64(50), true},
+		{"!=", float64(50), false},
+		{">", int64(49), true},
+		{">=", int64(50), true},
+		{"<", int64(50), false},
+		{"<=", int64(50), true},
+		{"", float64(50), true},
+		{"exists", nil, true},
+		{"not_exists", nil, false},
+	}
+
+	for _, tt := range tests {
+		processor := Override{
+			NameOverride: "matched",
+			Conditions:   []*condition{{Field: "usage", Operator: tt.operator, Value: tt.value}},
+		}
+
+		assert.NoError(t, processor.Init())
+		processed := processor.Apply(createUsageMetric(50))
+
+		assert.Equal(t, tt.expected, processed[0].Name() == "matched", "Operator %q", tt.operator)
+	}
+}
+
+func TestConditionOnTagPresence(t *testing.T) {
+	processor := Override{
+		NamePrefix: "core_",
+		Conditions: []*condition{{Tag: "cpu"}, {Tag: "missing", Operator: "not_exists"}},
+	}
+
+	assert.NoError(t, processor.Init())
+	processed := processor.Apply(createUsageMetric(50), createTestMetric())
+
+	assert.Equal(t, "core_cpu", processed[0].Name())
+	assert.Equal(t, "m1", processed[1].Name())
+}
+
+func TestConditionOnTagValue(t *testing.T) {
+	processor := Override{
+		NameSuffix: "_first",
+		Conditions: []*condition{{Tag: "cpu", Value: "cpu0"}},
+	}
+
+	assert.NoError(t, processor.Init())
+	processed := processor.Apply(createUsageMetric(50))
+
+	assert.Equal(t, "cpu_first", processed[0].Name())
+}
+
+func TestRemoveTagsAndFields(t *testing.T) {
+	processor := Override{RemoveTags: []string{"h*"}, RemoveFields: []string{"idle"}}
+
+	assert.NoError(t, processor.Init())
+	processed := processor.Apply(createUsageMetric(50))
+
+	assert.Equal(t, map[string]string{"cpu": "cpu0"}, processed[0].Tags())
+	assert.Equal(t, map[string]interface{}{"usage": float64(50)}, processed[0].Fields())
+}
+
+func TestRemoveAllFieldsDropsMetric(t *testing.T) {
+	processor := Override{
+		RemoveFields: []string{"*"},
+		Conditions:   []*condition{{Field: "usage", Operator: "<", Value: int64(10)}},
+	}
+
+	assert.NoError(t, processor.Init())
+	processed := processor.Apply(createUsageMetric(5), createUsageMetric(50))
+
+	assert.Equal(t, 1, len(processed))
+	assert.Equal(t, float64(50), processed[0].Fields()["usage"])
+}
+
+func TestInvalidCondition(t *testing.T) {
+	processor := Override{
+		NameOverride: "overridden",
+		Conditions:   []*condition{{Field: "usage", Operator: "~"}},
+	}
+
+	assert.Error(t, processor.Init())
+}
@@ -0,0 +1,61 @@
+package pivot
+
+import (
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+const sampleConfig = `
+  ## Tag to use for naming the new field.
+  tag_key = "name"
+  ## Field to use as the value of the new field.
+  value_key = "value"
+`
+
+type Pivot struct {
+	TagKey   string `toml:"tag_key"`
+	ValueKey string `toml:"value_key"`
+}
+
+func (p *Pivot) SampleConfig() string {
+	return sampleConfig
+}
+
+func (p *Pivot) Description() string {
+	return "Rotate a single valued metric into a multi field metric"
+}
+
+func (p *Pivot) Apply(metrics ...telegraf.Metric) []telegraf.Metric {
+	for _, m := range metrics {
+		key, ok := m.GetTag(p.TagKey)
+		if !ok {
+			continue
+		}
+
+		value, ok := m.GetField(p.ValueKey)
+		if !ok {
+			continue
+		}
+
+		m.RemoveTag(p.TagKey)
+
+		// The field list is modified when removing, so the keys are
+		// collected first.
+		keys := make([]string, 0, len(m.FieldList()))
+		for _, field := range m.FieldList() {
+			keys = append(keys, field.Key)
+		}
+		for _, key := range keys {
+			m.RemoveField(key)
+		}
+
+		m.AddField(key, value)
+	}
+	return metrics
+}
+
+func init() {
+	processors.Add("pivot", func() telegraf.Processor {
+		return &Pivot{}
+	})
+}
@@ -0,0 +1,329 @@
+package rate
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+const sampleConfig = `
+  ## Fields to compute the rate of, glob patterns are accepted.
+  fields = ["*"]
+
+  ## Suffix added to the field name of the computed rate.
+  # suffix = "_rate"
+
+  ## The computation to perform, one of:
+  ##   "derivative"              : The change of the value, may be negative.
+  ##   "non_negative_derivative" : The change of a counter, handling wraps
+  ##                               and resets of the counter.
+  # mode = "non_negative_derivative"
+
+  ## The rate is the change of the value per unit of time.
+  # unit = "1s"
+
+  ## Size of the counters in bits, either 32 or 64.  When set, a decrease of
+  ## a counter that was above half of its maximum value is handled as the
+  ## counter wrapping around, any other decrease is handled as a reset.  When
+  ## unset, all decreases are handled as resets.  Only used with the
+  ## non_negative_derivative mode.
+  # counter_bits = 0
+
+  ## If true, the fields used to compute the rate are removed.  Metrics left
+  ## with no fields are dropped.
+  # drop_original = false
+
+  ## Series not seen for longer than max_age are forgotten, the next value of
+  ## such a series is handled as its first value.  Set to 0 to keep all
+  ## series.
+  # max_age = "1h"
+`
+
+type Rate struct {
+	Fields       []string          `toml:"fields"`
+	Suffix       string            `toml:"suffix"`
+	Mode         string            `toml:"mode"`
+	Unit         internal.Duration `toml:"unit"`
+	CounterBits  int               `toml:"counter_bits"`
+	DropOriginal bool              `toml:"drop_original"`
+	MaxAge       internal.Duration `toml:"max_age"`
+
+	fieldFilter filter.Filter
+	series      map[uint64]*series
+	latest      time.Time
+	purgeTime   time.Time
+}
+
+// series holds the last values of the fields of a series and the time the
+// series was last seen.
+type series struct {
+	samples map[string]sample
+	time    time.Time
+}
+
+// sample is the last value of a field of a series.
+type sample struct {
+	value interface{}
+	time  time.Time
+}
+
+func NewRate() *Rate {
+	return &Rate{
+		Fields: []string{"*"},
+		Suffix: "_rate",
+		Mode:   "non_negative_derivative",
+		Unit:   internal.Duration{Duration: time.Second},
+		MaxAge: internal.Duration{Duration: time.Hour},
+	}
+}
+
+func (r *Rate) SampleConfig() string {
+	return sampleConfig
+}
+
+func (r *Rate) Description() string {
+	return "Compute the rate of change of fields, such as counters"
+}
+
+func (r *Rate) Init() error {
+	switch r.Mode {
+	case "derivative", "non_negative_derivative":
+	default:
+		return fmt.Errorf("invalid mode: %s", r.Mode)
+	}
+
+	switch r.CounterBits {
+	case 0, 32, 64:
+	default:
+		return fmt.Errorf("invalid counter_bits: %d", r.CounterBits)
+	}
+
+	if r.Unit.Duration <= 0 {
+		return fmt.Errorf("invalid unit: %s", r.Unit.Duration)
+	}
+
+	if r.MaxAge.Duration < 0 {
+		return fmt.Errorf("invalid max_age: %s", r.MaxAge.Duration)
+	}
+
+	r.series = make(map[uint64]*series)
+
+	var err error
+	r.fieldFilter, err = filter.Compile(r.Fields)
+	return err
+}
+
+func (r *Rate) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	for _, m := range in {
+		if m.Time().After(r.latest) {
+			r.latest = m.Time()
+		}
+	}
+	r.purge()
+
+	out := in[:0]
+	for _, m := range in {
+		if r.process(m) {
+			out = append(out, m)
+		} else {
+			m.Drop()
+		}
+	}
+	return out
+}
+
+// process adds the rates to the metric, it returns false if the metric is
+// left without fields.
+func (r *Rate) process(m telegraf.Metric) bool {
+	id := m.HashID()
+	s, ok := r.series[id]
+	if !ok || r.expired(s) {
+		s = &series{samples: make(map[string]sample)}
+		r.series[id] = s
+	}
+	if m.Time().After(s.time) {
+		s.time = m.Time()
+	}
+	last := s.samples
+
+	var matched []string
+	rates := make(map[string]float64)
+	for _, field := range m.FieldList() {
+		if !r.fieldFilter.Match(field.Key) || !isNumeric(field.Value) {
+			continue
+		}
+		matched = append(matched, field.Key)
+
+		cur := sample{value: field.Value, time: m.Time()}
+		prev, ok := last[field.Key]
+		last[field.Key] = cur
+		if !ok {
+			continue
+		}
+
+		if rate, ok := r.rate(prev, cur); ok {
+			rates[field.Key+r.Suffix] = rate
+		}
+	}
+
+	if r.DropOriginal {
+		for _, key := range matched {
+			m.RemoveField(key)
+		}
+	}
+
+	for key, rate := range rates {
+		m.AddField(key, rate)
+	}
+
+	return len(m.FieldList()) > 0
+}
+
+// expired returns true if the series has not been seen within max_age of the
+// newest metric.
+func (r *Rate) expired(s *series) bool {
+	return r.MaxAge.Duration > 0 && r.latest.Sub(s.time) >= r.MaxAge.Duration
+}
+
+// purge removes the expired series, it runs at most once per max_age.
+func (r *Rate) purge() {
+	if r.MaxAge.Duration <= 0 || r.latest.Sub(r.purgeTime) < r.MaxAge.Duration {
+		return
+	}
+	r.purgeTime = r.latest
+
+	for id, s := range r.series {
+		if r.expired(s) {
+			delete(r.series, id)
+		}
+	}
+}
+
+// rate returns the rate of change between the samples, false is returned if
+// there is no rate, such as when the counter was reset.
+func (r *Rate) rate(prev, cur sample) (float64, bool) {
+	elapsed := cur.time.Sub(prev.time)
+	if elapsed <= 0 {
+		return 0, false
+	}
+
+	var delta float64
+	var ok bool
+	if r.Mode == "derivative" {
+		delta, ok = difference(prev.value, cur.value), true
+	} else {
+		delta, ok = r.counterDifference(prev.value, cur.value)
+	}
+	if !ok {
+		return 0, false
+	}
+
+	return delta * float64(r.Unit.Duration) / float64(elapsed), true
+}
+
+// counterDifference returns the increase of a counter, handling counter
+// wraps.  False is returned if the counter was reset.
+func (r *Rate) counterDifference(prev, cur interface{}) (float64, bool) {
+	if !less(cur, prev) {
+		return difference(prev, cur), true
+	}
+
+	if r.CounterBits == 0 {
+		return 0, false
+	}
+
+	// Counters are never negative, the wrap is computed with unsigned
+	// integers unless the value is a float.
+	if p, c, ok := asUnsigned(prev, cur); ok {
+		max := uint64(math.MaxUint64)
+		if r.CounterBits == 32 {
+			max = math.MaxUint32
+		}
+		if p > max || p <= max/2 {
+			return 0, false
+		}
+		return float64(max-p) + float64(c) + 1, true
+	}
+
+	p, c := toFloat(prev), toFloat(cur)
+	max := math.Pow(2, float64(r.CounterBits))
+	if p >= max || p <= max/2 {
+		return 0, false
+	}
+	return max - p + c, true
+}
+
+func isNumeric(v interface{}) bool {
+	switch v.(type) {
+	case int64, uint64, float64:
+		return true
+	}
+	return false
+}
+
+func toFloat(v interface{}) float64 {
+	switch v := v.(type) {
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+// asUnsigned returns the values as unsigned integers if both are integers
+// that are not negative.
+func asUnsigned(a, b interface{}) (uint64, uint64, bool) {
+	toUnsigned := func(v interface{}) (uint64, bool) {
+		switch v := v.(type) {
+		case int64:
+			return uint64(v), v >= 0
+		case uint64:
+			return v, true
+		}
+		return 0, false
+	}
+
+	ua, ok := toUnsigned(a)
+	if !ok {
+		return 0, 0, false
+	}
+	ub, ok := toUnsigned(b)
+	if !ok {
+		return 0, 0, false
+	}
+	return ua, ub, true
+}
+
+// less returns true if a is less than b, integers are compared exactly.
+func less(a, b interface{}) bool {
+	if ua, ub, ok := asUnsigned(a, b); ok {
+		return ua < ub
+	}
+	return toFloat(a) < toFloat(b)
+}
+
+// difference returns b - a, integers are subtracted exactly before the
+// conversion to float.
+func difference(a, b interface{}) float64 {
+	if ua, ub, ok := asUnsigned(a, b); ok {
+		if ub >= ua {
+			return float64(ub - ua)
+		}
+		return -float64(ua - ub)
+	}
+	return toFloat(b) - toFloat(a)
+}
+
+func init() {
+	processors.Add("rate", func() telegraf.Processor {
+		return NewRate()
+	})
+}
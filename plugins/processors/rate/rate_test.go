@@ -0,0 +1,381 @@
+package rate
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRate(t *testing.T) {
+	var tests = []struct {
+		name     string
+		rate     *Rate
+		input    []telegraf.Metric
+		expected []telegraf.Metric
+	}{
+		{
+			name: "first value has no rate",
+			rate: NewRate(),
+			input: []telegraf.Metric{
+				testutil.MustMetric("net",
+					map[string]string{"interface": "eth0"},
+					map[string]interface{}{"bytes_recv": int64(100)},
+					time.Unix(0, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("net",
+					map[string]string{"interface": "eth0"},
+					map[string]interface{}{"bytes_recv": int64(100)},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "rate per series",
+			rate: NewRate(),
+			input: []telegraf.Metric{
+				testutil.MustMetric("net",
+					map[string]string{"interface": "eth0"},
+					map[string]interface{}{"bytes_recv": int64(100)},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{"interface": "eth1"},
+					map[string]interface{}{"bytes_recv": int64(1000)},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{"interface": "eth0"},
+					map[string]interface{}{"bytes_recv": int64(300)},
+					time.Unix(10, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{"interface": "eth1"},
+					map[string]interface{}{"bytes_recv": int64(1500)},
+					time.Unix(5, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("net",
+					map[string]string{"interface": "eth0"},
+					map[string]interface{}{"bytes_recv": int64(100)},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{"interface": "eth1"},
+					map[string]interface{}{"bytes_recv": int64(1000)},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{"interface": "eth0"},
+					map[string]interface{}{"bytes_recv": int64(300), "bytes_recv_rate": 20.0},
+					time.Unix(10, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{"interface": "eth1"},
+					map[string]interface{}{"bytes_recv": int64(1500), "bytes_recv_rate": 100.0},
+					time.Unix(5, 0),
+				),
+			},
+		},
+		{
+			name: "field filter and unit",
+			rate: &Rate{
+				Fields: []string{"bytes_*"},
+				Suffix: "_per_min",
+				Mode:   "non_negative_derivative",
+				Unit:   internal.Duration{Duration: time.Minute},
+			},
+			input: []telegraf.Metric{
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"bytes_recv": uint64(0), "err_in": int64(0)},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"bytes_recv": uint64(30), "err_in": int64(1)},
+					time.Unix(30, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"bytes_recv": uint64(0), "err_in": int64(0)},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"bytes_recv": uint64(30), "err_in": int64(1), "bytes_recv_per_min": 60.0},
+					time.Unix(30, 0),
+				),
+			},
+		},
+		{
+			name: "derivative may be negative",
+			rate: &Rate{
+				Fields: []string{"*"},
+				Suffix: "_rate",
+				Mode:   "derivative",
+				Unit:   internal.Duration{Duration: time.Second},
+			},
+			input: []telegraf.Metric{
+				testutil.MustMetric("disk",
+					map[string]string{},
+					map[string]interface{}{"free": 50.0},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("disk",
+					map[string]string{},
+					map[string]interface{}{"free": 40.0},
+					time.Unix(2, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("disk",
+					map[string]string{},
+					map[string]interface{}{"free": 50.0},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("disk",
+					map[string]string{},
+					map[string]interface{}{"free": 40.0, "free_rate": -5.0},
+					time.Unix(2, 0),
+				),
+			},
+		},
+		{
+			name: "counter reset",
+			rate: NewRate(),
+			input: []telegraf.Metric{
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"packets": int64(1000)},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"packets": int64(10)},
+					time.Unix(1, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"packets": int64(20)},
+					time.Unix(2, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"packets": int64(1000)},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"packets": int64(10)},
+					time.Unix(1, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"packets": int64(20), "packets_rate": 10.0},
+					time.Unix(2, 0),
+				),
+			},
+		},
+		{
+			name: "32 bit counter wrap",
+			rate: &Rate{
+				Fields:      []string{"*"},
+				Suffix:      "_rate",
+				Mode:        "non_negative_derivative",
+				Unit:        internal.Duration{Duration: time.Second},
+				CounterBits: 32,
+			},
+			input: []telegraf.Metric{
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"octets": int64(math.MaxUint32 - 9)},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"octets": int64(10)},
+					time.Unix(1, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"octets": int64(5)},
+					time.Unix(2, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"octets": int64(math.MaxUint32 - 9)},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"octets": int64(10), "octets_rate": 20.0},
+					time.Unix(1, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"octets": int64(5)},
+					time.Unix(2, 0),
+				),
+			},
+		},
+		{
+			name: "64 bit counter wrap",
+			rate: &Rate{
+				Fields:      []string{"*"},
+				Suffix:      "_rate",
+				Mode:        "non_negative_derivative",
+				Unit:        internal.Duration{Duration: time.Second},
+				CounterBits: 64,
+			},
+			input: []telegraf.Metric{
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"octets": uint64(math.MaxUint64 - 4)},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"octets": uint64(5)},
+					time.Unix(1, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"octets": uint64(math.MaxUint64 - 4)},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"octets": uint64(5), "octets_rate": 10.0},
+					time.Unix(1, 0),
+				),
+			},
+		},
+		{
+			name: "drop original",
+			rate: &Rate{
+				Fields:       []string{"bytes_*"},
+				Suffix:       "_rate",
+				Mode:         "non_negative_derivative",
+				Unit:         internal.Duration{Duration: time.Second},
+				DropOriginal: true,
+			},
+			input: []telegraf.Metric{
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"bytes_recv": int64(0)},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"bytes_recv": int64(10), "speed": int64(1000)},
+					time.Unix(1, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"bytes_recv_rate": 10.0, "speed": int64(1000)},
+					time.Unix(1, 0),
+				),
+			},
+		},
+		{
+			name: "same time has no rate",
+			rate: NewRate(),
+			input: []telegraf.Metric{
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"packets": int64(0)},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"packets": int64(10)},
+					time.Unix(0, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"packets": int64(0)},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("net",
+					map[string]string{},
+					map[string]interface{}{"packets": int64(10)},
+					time.Unix(0, 0),
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.rate.Init())
+
+			var actual []telegraf.Metric
+			for _, m := range tt.input {
+				actual = append(actual, tt.rate.Apply(m)...)
+			}
+			testutil.RequireMetricsEqual(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestInvalidConfiguration(t *testing.T) {
+	rate := NewRate()
+	rate.Mode = "integral"
+	require.Error(t, rate.Init())
+}
+
+func TestMaxAge(t *testing.T) {
+	rate := NewRate()
+	rate.MaxAge = internal.Duration{Duration: time.Minute}
+	require.NoError(t, rate.Init())
+
+	newMetric := func(host string, value int64, tm time.Time) telegraf.Metric {
+		return testutil.MustMetric("net",
+			map[string]string{"host": host},
+			map[string]interface{}{"packets": value},
+			tm,
+		)
+	}
+
+	now := time.Unix(0, 0)
+	rate.Apply(newMetric("a", 0, now))
+	rate.Apply(newMetric("b", 0, now.Add(30*time.Second)))
+	require.Len(t, rate.series, 2)
+
+	// Series a is expired, it is removed when the next metric is added and
+	// its next value has no rate.
+	rate.Apply(newMetric("b", 10, now.Add(2*time.Minute)))
+	require.Len(t, rate.series, 1)
+
+	actual := rate.Apply(newMetric("a", 10, now.Add(2*time.Minute)))
+	expected := []telegraf.Metric{
+		newMetric("a", 10, now.Add(2*time.Minute)),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestInvalidMaxAge(t *testing.T) {
+	rate := NewRate()
+	rate.MaxAge = internal.Duration{Duration: -time.Minute}
+	require.Error(t, rate.Init())
+}
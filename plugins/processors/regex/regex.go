@@ -1,23 +1,31 @@
 package regex
 
 import (
+	"fmt"
 	"regexp"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
 	"github.com/influxdata/telegraf/plugins/processors"
 )
 
 type Regex struct {
-	Tags       []converter
-	Fields     []converter
-	regexCache map[string]*regexp.Regexp
+	Tags         []converter
+	Fields       []converter
+	TagRename    []converter `toml:"tag_rename"`
+	FieldRename  []converter `toml:"field_rename"`
+	MetricRename []converter `toml:"metric_rename"`
+	regexCache   map[string]*regexp.Regexp
+	filterCache  map[string]filter.Filter
 }
 
 type converter struct {
-	Key         string
-	Pattern     string
-	Replacement string
-	ResultKey   string
+	Key           string
+	Pattern       string
+	Replacement   string
+	ResultKey     string
+	OnConflict    string `toml:"on_conflict"`
+	DropOnNoMatch bool   `toml:"drop_on_no_match"`
 }
 
 const sampleConfig = `
@@ -47,11 +55,38 @@ const sampleConfig = `
   #   pattern = ".*category=(\\w+).*"
   #   replacement = "${1}"
   #   result_key = "search_category"
+
+  ## The key of tag and field conversions may be a glob pattern, to apply the
+  ## conversion to all matching tags or fields
+  # [[processors.regex.tags]]
+  #   key = "*"
+  #   pattern = "^\\s+|\\s+$"
+  #   replacement = ""
+
+  ## Conversions of tag and field keys, and measurement names
+  # [[processors.regex.tag_rename]]
+  #   pattern = "^k8s_(\\w+)$"
+  #   replacement = "kubernetes_${1}"
+  #   ## What to do if a tag with the new key already exists, "overwrite" the
+  #   ## existing tag or "keep" it and leave the renamed tag unchanged
+  #   # on_conflict = "overwrite"
+
+  # [[processors.regex.field_rename]]
+  #   pattern = "^(\\w+)_ms$"
+  #   replacement = "${1}_milliseconds"
+
+  # [[processors.regex.metric_rename]]
+  #   pattern = "^nginx_(\\w+)$"
+  #   replacement = "web_${1}"
+  #   ## Drop metrics whose name does not match the pattern, this option is
+  #   ## available for all conversions
+  #   # drop_on_no_match = false
 `
 
 func NewRegex() *Regex {
 	return &Regex{
-		regexCache: make(map[string]*regexp.Regexp),
+		regexCache:  make(map[string]*regexp.Regexp),
+		filterCache: make(map[string]filter.Filter),
 	}
 }
 
@@ -63,48 +98,218 @@ func (r *Regex) Description() string {
 	return "Transforms tag and field values with regex pattern"
 }
 
+func (r *Regex) Init() error {
+	for _, c := range r.Tags {
+		if err := r.compileKey(c.Key); err != nil {
+			return err
+		}
+	}
+	for _, c := range r.Fields {
+		if err := r.compileKey(c.Key); err != nil {
+			return err
+		}
+	}
+
+	for _, converters := range [][]converter{r.Tags, r.Fields, r.TagRename, r.FieldRename, r.MetricRename} {
+		for _, c := range converters {
+			switch c.OnConflict {
+			case "", "overwrite", "keep":
+			default:
+				return fmt.Errorf("invalid on_conflict: %s", c.OnConflict)
+			}
+
+			regex, err := regexp.Compile(c.Pattern)
+			if err != nil {
+				return fmt.Errorf("invalid pattern %q: %v", c.Pattern, err)
+			}
+			r.regexCache[c.Pattern] = regex
+		}
+	}
+	return nil
+}
+
+// compileKey compiles the key of a conversion, which may be a glob pattern.
+func (r *Regex) compileKey(key string) error {
+	f, err := filter.Compile([]string{key})
+	if err != nil {
+		return fmt.Errorf("invalid key %q: %v", key, err)
+	}
+	r.filterCache[key] = f
+	return nil
+}
+
 func (r *Regex) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	out := in[:0]
 	for _, metric := range in {
-		for _, converter := range r.Tags {
-			if value, ok := metric.GetTag(converter.Key); ok {
-				if key, newValue := r.convert(converter, value); newValue != "" {
-					metric.AddTag(key, newValue)
-				}
+		if r.process(metric) {
+			out = append(out, metric)
+		} else {
+			metric.Drop()
+		}
+	}
+
+	return out
+}
+
+// process applies the conversions to the metric, it returns false if the
+// metric should be dropped.
+func (r *Regex) process(metric telegraf.Metric) bool {
+	for _, converter := range r.Tags {
+		matched := false
+		for _, key := range r.matchingKeys(converter.Key, tagKeys(metric)) {
+			value, _ := metric.GetTag(key)
+			if key, newValue := r.convert(converter, key, value); newValue != "" {
+				metric.AddTag(key, newValue)
 			}
+			matched = matched || r.matches(converter, value)
 		}
+		if converter.DropOnNoMatch && !matched {
+			return false
+		}
+	}
 
-		for _, converter := range r.Fields {
-			if value, ok := metric.GetField(converter.Key); ok {
-				switch value := value.(type) {
-				case string:
-					if key, newValue := r.convert(converter, value); newValue != "" {
-						metric.AddField(key, newValue)
-					}
+	for _, converter := range r.Fields {
+		matched := false
+		for _, key := range r.matchingKeys(converter.Key, fieldKeys(metric)) {
+			value, _ := metric.GetField(key)
+			switch value := value.(type) {
+			case string:
+				if key, newValue := r.convert(converter, key, value); newValue != "" {
+					metric.AddField(key, newValue)
 				}
+				matched = matched || r.matches(converter, value)
+			}
+		}
+		if converter.DropOnNoMatch && !matched {
+			return false
+		}
+	}
+
+	for _, converter := range r.TagRename {
+		matched := false
+		for _, key := range tagKeys(metric) {
+			if !r.matches(converter, key) {
+				continue
+			}
+			matched = true
+
+			newKey := r.replace(converter, key)
+			if newKey == key || newKey == "" {
+				continue
+			}
+			if _, exists := metric.GetTag(newKey); exists && converter.OnConflict == "keep" {
+				continue
+			}
+			value, _ := metric.GetTag(key)
+			metric.RemoveTag(key)
+			metric.AddTag(newKey, value)
+		}
+		if converter.DropOnNoMatch && !matched {
+			return false
+		}
+	}
+
+	for _, converter := range r.FieldRename {
+		matched := false
+		for _, key := range fieldKeys(metric) {
+			if !r.matches(converter, key) {
+				continue
+			}
+			matched = true
+
+			newKey := r.replace(converter, key)
+			if newKey == key || newKey == "" {
+				continue
+			}
+			if _, exists := metric.GetField(newKey); exists && converter.OnConflict == "keep" {
+				continue
+			}
+			value, _ := metric.GetField(key)
+			metric.RemoveField(key)
+			metric.AddField(newKey, value)
+		}
+		if converter.DropOnNoMatch && !matched {
+			return false
+		}
+	}
+
+	for _, converter := range r.MetricRename {
+		if !r.matches(converter, metric.Name()) {
+			if converter.DropOnNoMatch {
+				return false
 			}
+			continue
+		}
+		if name := r.replace(converter, metric.Name()); name != "" {
+			metric.SetName(name)
+		}
+	}
+
+	return true
+}
+
+// matchingKeys returns the keys matching the key of a conversion, which may
+// be a glob pattern compiled by Init.
+func (r *Regex) matchingKeys(pattern string, keys []string) []string {
+	f := r.filterCache[pattern]
+
+	var matching []string
+	for _, key := range keys {
+		if (f == nil && key == pattern) || (f != nil && f.Match(key)) {
+			matching = append(matching, key)
 		}
 	}
+	return matching
+}
+
+// tagKeys returns the keys of the tags, collected so the tags may be modified
+// while iterating over them.
+func tagKeys(metric telegraf.Metric) []string {
+	keys := make([]string, 0, len(metric.TagList()))
+	for _, tag := range metric.TagList() {
+		keys = append(keys, tag.Key)
+	}
+	return keys
+}
 
-	return in
+// fieldKeys returns the keys of the fields, collected so the fields may be
+// modified while iterating over them.
+func fieldKeys(metric telegraf.Metric) []string {
+	keys := make([]string, 0, len(metric.FieldList()))
+	for _, field := range metric.FieldList() {
+		keys = append(keys, field.Key)
+	}
+	return keys
+}
+
+// matches returns true if the pattern of the conversion, compiled by Init,
+// matches the value.
+func (r *Regex) matches(c converter, src string) bool {
+	regex, ok := r.regexCache[c.Pattern]
+	return ok && regex.MatchString(src)
 }
 
-func (r *Regex) convert(c converter, src string) (string, string) {
-	regex, compiled := r.regexCache[c.Pattern]
-	if !compiled {
-		regex = regexp.MustCompile(c.Pattern)
-		r.regexCache[c.Pattern] = regex
+// replace returns the value with the matches of the pattern replaced, the
+// value is unchanged if the pattern was not compiled.
+func (r *Regex) replace(c converter, src string) string {
+	regex, ok := r.regexCache[c.Pattern]
+	if !ok {
+		return src
 	}
+	return regex.ReplaceAllString(src, c.Replacement)
+}
 
+func (r *Regex) convert(c converter, key string, src string) (string, string) {
 	value := ""
-	if c.ResultKey == "" || regex.MatchString(src) {
-		value = regex.ReplaceAllString(src, c.Replacement)
+	if c.ResultKey == "" || r.matches(c, src) {
+		value = r.replace(c, src)
 	}
 
 	if c.ResultKey != "" {
 		return c.ResultKey, value
 	}
 
-	return c.Key, value
+	return key, value
 }
 
 func init() {
@@ -77,6 +77,7 @@ func TestFieldConversions(t *testing.T) {
 			test.converter,
 		}
 
+		assert.NoError(t, regex.Init())
 		processed := regex.Apply(newM1())
 
 		expectedTags := map[string]string{
@@ -130,6 +131,7 @@ func TestTagConversions(t *testing.T) {
 			test.converter,
 		}
 
+		assert.NoError(t, regex.Init())
 		processed := regex.Apply(newM1())
 
 		expectedFields := map[string]interface{}{
@@ -173,6 +175,7 @@ func TestMultipleConversions(t *testing.T) {
 		},
 	}
 
+	assert.NoError(t, regex.Init())
 	processed := regex.Apply(newM2())
 
 	expectedFields := map[string]interface{}{
@@ -241,12 +244,270 @@ func TestNoMatches(t *testing.T) {
 			test.converter,
 		}
 
+		assert.NoError(t, regex.Init())
 		processed := regex.Apply(newM1())
 
 		assert.Equal(t, test.expectedFields, processed[0].Fields(), test.message)
 	}
 }
 
+func TestGlobKeys(t *testing.T) {
+	regex := NewRegex()
+	regex.Tags = []converter{
+		{
+			Key:         "*",
+			Pattern:     "^GET$",
+			Replacement: "get",
+		},
+	}
+	regex.Fields = []converter{
+		{
+			Key:         "req*",
+			Pattern:     "^/users/\\d+/$",
+			Replacement: "/users/{id}/",
+		},
+	}
+
+	assert.NoError(t, regex.Init())
+	processed := regex.Apply(newM1())
+
+	expectedFields := map[string]interface{}{
+		"request": "/users/{id}/",
+	}
+	expectedTags := map[string]string{
+		"verb":      "get",
+		"resp_code": "200",
+	}
+
+	assert.Equal(t, expectedFields, processed[0].Fields())
+	assert.Equal(t, expectedTags, processed[0].Tags())
+}
+
+func TestKeyRenames(t *testing.T) {
+	tests := []struct {
+		message        string
+		tagRename      []converter
+		fieldRename    []converter
+		expectedTags   map[string]string
+		expectedFields map[string]interface{}
+	}{
+		{
+			message: "Should rename matching tag keys",
+			tagRename: []converter{
+				{
+					Pattern:     "^(\\w+)_code$",
+					Replacement: "${1}",
+				},
+			},
+			expectedTags: map[string]string{
+				"verb": "GET",
+				"resp": "200",
+			},
+			expectedFields: map[string]interface{}{
+				"request": "/users/42/",
+			},
+		},
+		{
+			message: "Should rename matching field keys",
+			fieldRename: []converter{
+				{
+					Pattern:     "^request$",
+					Replacement: "path",
+				},
+			},
+			expectedTags: map[string]string{
+				"verb":      "GET",
+				"resp_code": "200",
+			},
+			expectedFields: map[string]interface{}{
+				"path": "/users/42/",
+			},
+		},
+		{
+			message: "Should overwrite existing tag by default",
+			tagRename: []converter{
+				{
+					Pattern:     "^resp_code$",
+					Replacement: "verb",
+				},
+			},
+			expectedTags: map[string]string{
+				"verb": "200",
+			},
+			expectedFields: map[string]interface{}{
+				"request": "/users/42/",
+			},
+		},
+		{
+			message: "Should keep existing tag on conflict",
+			tagRename: []converter{
+				{
+					Pattern:     "^resp_code$",
+					Replacement: "verb",
+					OnConflict:  "keep",
+				},
+			},
+			expectedTags: map[string]string{
+				"verb":      "GET",
+				"resp_code": "200",
+			},
+			expectedFields: map[string]interface{}{
+				"request": "/users/42/",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		regex := NewRegex()
+		regex.TagRename = test.tagRename
+		regex.FieldRename = test.fieldRename
+
+		assert.NoError(t, regex.Init())
+		processed := regex.Apply(newM1())
+
+		assert.Equal(t, test.expectedTags, processed[0].Tags(), test.message)
+		assert.Equal(t, test.expectedFields, processed[0].Fields(), test.message)
+		assert.Equal(t, "access_log", processed[0].Name(), "Should not change name")
+	}
+}
+
+func TestMetricRename(t *testing.T) {
+	regex := NewRegex()
+	regex.MetricRename = []converter{
+		{
+			Pattern:     "^access_(\\w+)$",
+			Replacement: "http_${1}",
+		},
+		{
+			Pattern:     "^not_match$",
+			Replacement: "x",
+		},
+	}
+
+	assert.NoError(t, regex.Init())
+	processed := regex.Apply(newM1())
+
+	assert.Equal(t, "http_log", processed[0].Name())
+}
+
+func TestDropOnNoMatch(t *testing.T) {
+	tests := []struct {
+		message       string
+		tags          []converter
+		fields        []converter
+		fieldRename   []converter
+		metricRename  []converter
+		expectDropped bool
+	}{
+		{
+			message: "Should keep metric if tag matches",
+			tags: []converter{
+				{
+					Key:           "resp_code",
+					Pattern:       "^2",
+					Replacement:   "2xx",
+					DropOnNoMatch: true,
+				},
+			},
+		},
+		{
+			message: "Should drop metric if tag doesn't match",
+			tags: []converter{
+				{
+					Key:           "resp_code",
+					Pattern:       "^5",
+					Replacement:   "5xx",
+					DropOnNoMatch: true,
+				},
+			},
+			expectDropped: true,
+		},
+		{
+			message: "Should drop metric if field is missing",
+			fields: []converter{
+				{
+					Key:           "not_exists",
+					Pattern:       ".*",
+					DropOnNoMatch: true,
+				},
+			},
+			expectDropped: true,
+		},
+		{
+			message: "Should drop metric if no field key matches",
+			fieldRename: []converter{
+				{
+					Pattern:       "^not_match$",
+					Replacement:   "x",
+					DropOnNoMatch: true,
+				},
+			},
+			expectDropped: true,
+		},
+		{
+			message: "Should drop metric if name doesn't match",
+			metricRename: []converter{
+				{
+					Pattern:       "^nginx",
+					Replacement:   "web",
+					DropOnNoMatch: true,
+				},
+			},
+			expectDropped: true,
+		},
+	}
+
+	for _, test := range tests {
+		regex := NewRegex()
+		regex.Tags = test.tags
+		regex.Fields = test.fields
+		regex.FieldRename = test.fieldRename
+		regex.MetricRename = test.metricRename
+
+		assert.NoError(t, regex.Init())
+		processed := regex.Apply(newM1(), newM2())
+
+		if test.expectDropped {
+			assert.Len(t, processed, 0, test.message)
+		} else {
+			assert.Len(t, processed, 2, test.message)
+		}
+	}
+}
+
+func TestInvalidConfiguration(t *testing.T) {
+	tests := []struct {
+		message string
+		regex   *Regex
+	}{
+		{
+			message: "invalid tag pattern",
+			regex:   &Regex{Tags: []converter{{Key: "verb", Pattern: "("}}},
+		},
+		{
+			message: "invalid field key",
+			regex:   &Regex{Fields: []converter{{Key: "[", Pattern: "x"}}},
+		},
+		{
+			message: "invalid rename pattern",
+			regex:   &Regex{TagRename: []converter{{Pattern: "(?P<"}}},
+		},
+		{
+			message: "invalid on_conflict",
+			regex:   &Regex{FieldRename: []converter{{Pattern: "x", OnConflict: "merge"}}},
+		},
+	}
+
+	for _, test := range tests {
+		regex := NewRegex()
+		regex.Tags = test.regex.Tags
+		regex.Fields = test.regex.Fields
+		regex.TagRename = test.regex.TagRename
+		regex.FieldRename = test.regex.FieldRename
+		assert.Error(t, regex.Init(), test.message)
+	}
+}
+
 func BenchmarkConversions(b *testing.B) {
 	regex := NewRegex()
 	regex.Tags = []converter{
@@ -265,6 +526,10 @@ func BenchmarkConversions(b *testing.B) {
 		},
 	}
 
+	if err := regex.Init(); err != nil {
+		b.Fatal(err)
+	}
+
 	for n := 0; n < b.N; n++ {
 		processed := regex.Apply(newM1())
 		_ = processed
@@ -3,9 +3,20 @@ package processors
 import "github.com/influxdata/telegraf"
 
 type Creator func() telegraf.Processor
+type StreamingCreator func() telegraf.StreamingProcessor
 
-var Processors = map[string]Creator{}
+// Processors contains all registered processors.  Processors added with Add
+// are wrapped so that they can be run as a telegraf.StreamingProcessor.
+var Processors = map[string]StreamingCreator{}
 
+// Add registers a telegraf.Processor.
 func Add(name string, creator Creator) {
+	Processors[name] = func() telegraf.StreamingProcessor {
+		return NewStreamingProcessorFromProcessor(creator())
+	}
+}
+
+// AddStreaming registers a telegraf.StreamingProcessor.
+func AddStreaming(name string, creator StreamingCreator) {
 	Processors[name] = creator
 }
@@ -0,0 +1,63 @@
+package reverse_dns
+
+import (
+	"sync"
+	"time"
+)
+
+// cache holds the names of resolved addresses until their TTL expires.
+// Failed lookups are cached with an empty name, so unresolvable addresses are
+// not looked up for every metric.
+type cache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu          sync.Mutex
+	entries     map[string]cacheEntry
+	nextCleanup time.Time
+}
+
+type cacheEntry struct {
+	name    string
+	expires time.Time
+}
+
+func newCache(ttl time.Duration, now func() time.Time) *cache {
+	return &cache{
+		ttl:     ttl,
+		now:     now,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// get returns the name of the address, ok is false if the address is not in
+// the cache.
+func (c *cache) get(addr string) (name string, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[addr]
+	if !ok || !c.now().Before(e.expires) {
+		return "", false
+	}
+	return e.name, true
+}
+
+func (c *cache) set(addr, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.entries[addr] = cacheEntry{name: name, expires: now.Add(c.ttl)}
+
+	// Expired entries are removed once per TTL so the cache does not grow
+	// with addresses that are no longer seen.
+	if !now.Before(c.nextCleanup) {
+		for addr, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, addr)
+			}
+		}
+		c.nextCleanup = now.Add(c.ttl)
+	}
+}
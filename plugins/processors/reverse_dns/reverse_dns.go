@@ -0,0 +1,242 @@
+package reverse_dns
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+const sampleConfig = `
+  ## How long the name of an address is cached.  Addresses that could not be
+  ## resolved are cached for the same time.
+  # cache_ttl = "24h"
+
+  ## Timeout of each lookup.
+  # lookup_timeout = "3s"
+
+  ## Maximum number of lookups in progress at once.
+  # max_parallel_lookups = 10
+
+  ## If false, metrics are held until their addresses are resolved, and
+  ## metrics are not accepted while all lookups are in progress.  This may
+  ## change the order of metrics.
+  ## If true, metrics are passed on immediately with the names found in the
+  ## cache, and addresses not in the cache are resolved in the background for
+  ## later metrics.
+  # pass_through = false
+
+  [[processors.reverse_dns.lookup]]
+    ## Tag or field containing the address to resolve, only one of tag or
+    ## field can be set.
+    tag = "source"
+    # field = "source"
+
+    ## Tag or field to store the name in, of the same kind as the address.
+    ## If unset the address is replaced by the name.
+    dest = "source_name"
+`
+
+type ReverseDNS struct {
+	CacheTTL           internal.Duration `toml:"cache_ttl"`
+	LookupTimeout      internal.Duration `toml:"lookup_timeout"`
+	MaxParallelLookups int               `toml:"max_parallel_lookups"`
+	PassThrough        bool              `toml:"pass_through"`
+	Lookups            []lookup          `toml:"lookup"`
+
+	acc        telegraf.Accumulator
+	cache      *cache
+	sem        chan struct{}
+	wg         sync.WaitGroup
+	lookupAddr func(ctx context.Context, addr string) ([]string, error)
+}
+
+type lookup struct {
+	Tag   string `toml:"tag"`
+	Field string `toml:"field"`
+	Dest  string `toml:"dest"`
+}
+
+func NewReverseDNS() *ReverseDNS {
+	return &ReverseDNS{
+		CacheTTL:           internal.Duration{Duration: 24 * time.Hour},
+		LookupTimeout:      internal.Duration{Duration: 3 * time.Second},
+		MaxParallelLookups: 10,
+		lookupAddr:         net.DefaultResolver.LookupAddr,
+	}
+}
+
+func (r *ReverseDNS) SampleConfig() string {
+	return sampleConfig
+}
+
+func (r *ReverseDNS) Description() string {
+	return "Resolve addresses in tags and fields to names using reverse DNS"
+}
+
+func (r *ReverseDNS) Start(acc telegraf.Accumulator) error {
+	if r.MaxParallelLookups <= 0 {
+		return errors.New("max_parallel_lookups must be greater than zero")
+	}
+
+	for _, l := range r.Lookups {
+		if (l.Tag == "") == (l.Field == "") {
+			return errors.New("exactly one of tag or field must be set for each lookup")
+		}
+	}
+
+	r.acc = acc
+	r.cache = newCache(r.CacheTTL.Duration, time.Now)
+	r.sem = make(chan struct{}, r.MaxParallelLookups)
+	return nil
+}
+
+func (r *ReverseDNS) Add(m telegraf.Metric, acc telegraf.Accumulator) error {
+	addrs := r.addresses(m)
+
+	var missing []string
+	for _, addr := range addrs {
+		if _, ok := r.cache.get(addr); !ok {
+			missing = append(missing, addr)
+		}
+	}
+
+	if len(missing) == 0 {
+		r.setNames(m)
+		acc.AddMetric(m)
+		return nil
+	}
+
+	if r.PassThrough {
+		for _, addr := range missing {
+			select {
+			case r.sem <- struct{}{}:
+				r.wg.Add(1)
+				go func(addr string) {
+					defer r.wg.Done()
+					defer func() { <-r.sem }()
+					r.resolve(addr)
+				}(addr)
+			default:
+				// All lookups are in progress, the address is resolved
+				// for a later metric.
+			}
+		}
+		r.setNames(m)
+		acc.AddMetric(m)
+		return nil
+	}
+
+	r.sem <- struct{}{}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer func() { <-r.sem }()
+		for _, addr := range missing {
+			r.resolve(addr)
+		}
+		r.setNames(m)
+		acc.AddMetric(m)
+	}()
+	return nil
+}
+
+func (r *ReverseDNS) Stop() error {
+	r.wg.Wait()
+	return nil
+}
+
+// addresses returns the addresses to resolve for the metric.
+func (r *ReverseDNS) addresses(m telegraf.Metric) []string {
+	var addrs []string
+	for _, l := range r.Lookups {
+		if addr, ok := l.address(m); ok {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// setNames sets the names found in the cache on the metric.
+func (r *ReverseDNS) setNames(m telegraf.Metric) {
+	for _, l := range r.Lookups {
+		addr, ok := l.address(m)
+		if !ok {
+			continue
+		}
+
+		name, ok := r.cache.get(addr)
+		if !ok || name == "" {
+			continue
+		}
+
+		if l.Tag != "" {
+			dest := l.Tag
+			if l.Dest != "" {
+				dest = l.Dest
+			}
+			m.AddTag(dest, name)
+		} else {
+			dest := l.Field
+			if l.Dest != "" {
+				dest = l.Dest
+			}
+			m.AddField(dest, name)
+		}
+	}
+}
+
+// resolve looks up the name of the address and caches it.
+func (r *ReverseDNS) resolve(addr string) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.LookupTimeout.Duration)
+	defer cancel()
+
+	var name string
+	names, err := r.lookupAddr(ctx, addr)
+	if err != nil {
+		log.Printf("D! [processors.reverse_dns] Could not resolve %s: %v", addr, err)
+	} else if len(names) > 0 {
+		name = strings.TrimSuffix(names[0], ".")
+	}
+	r.cache.set(addr, name)
+}
+
+// address returns the address in the tag or field of the lookup, ok is false
+// if the metric does not contain a valid IP address.
+func (l *lookup) address(m telegraf.Metric) (string, bool) {
+	var addr string
+	if l.Tag != "" {
+		value, ok := m.GetTag(l.Tag)
+		if !ok {
+			return "", false
+		}
+		addr = value
+	} else {
+		value, ok := m.GetField(l.Field)
+		if !ok {
+			return "", false
+		}
+		addr, ok = value.(string)
+		if !ok {
+			return "", false
+		}
+	}
+
+	if net.ParseIP(addr) == nil {
+		return "", false
+	}
+	return addr, true
+}
+
+func init() {
+	processors.AddStreaming("reverse_dns", func() telegraf.StreamingProcessor {
+		return NewReverseDNS()
+	})
+}
@@ -0,0 +1,234 @@
+package reverse_dns
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// resolver is a fake DNS resolver counting the lookups of each address.
+type resolver struct {
+	sync.Mutex
+	names   map[string]string
+	lookups map[string]int
+}
+
+func newResolver(names map[string]string) *resolver {
+	return &resolver{names: names, lookups: make(map[string]int)}
+}
+
+func (r *resolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.lookups[addr]++
+	name, ok := r.names[addr]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return []string{name}, nil
+}
+
+func TestResolve(t *testing.T) {
+	plugin := NewReverseDNS()
+	plugin.Lookups = []lookup{
+		{Tag: "source", Dest: "source_name"},
+		{Field: "client"},
+	}
+	r := newResolver(map[string]string{
+		"192.0.2.1": "host1.example.org.",
+		"192.0.2.2": "host2.example.org.",
+	})
+	plugin.lookupAddr = r.LookupAddr
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+
+	input := []telegraf.Metric{
+		testutil.MustMetric("ping",
+			map[string]string{"source": "192.0.2.1"},
+			map[string]interface{}{"client": "192.0.2.2"},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("ping",
+			map[string]string{"source": "192.0.2.3"},
+			map[string]interface{}{"client": "not an address"},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("ping",
+			map[string]string{},
+			map[string]interface{}{"value": 42},
+			time.Unix(0, 0),
+		),
+	}
+	for _, m := range input {
+		require.NoError(t, plugin.Add(m, &acc))
+	}
+	require.NoError(t, plugin.Stop())
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("ping",
+			map[string]string{"source": "192.0.2.1", "source_name": "host1.example.org"},
+			map[string]interface{}{"client": "host2.example.org"},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("ping",
+			map[string]string{"source": "192.0.2.3"},
+			map[string]interface{}{"client": "not an address"},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("ping",
+			map[string]string{},
+			map[string]interface{}{"value": 42},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.SortMetrics())
+}
+
+func TestCache(t *testing.T) {
+	plugin := NewReverseDNS()
+	plugin.MaxParallelLookups = 1
+	plugin.Lookups = []lookup{{Tag: "source"}}
+	r := newResolver(map[string]string{"192.0.2.1": "host1.example.org."})
+	plugin.lookupAddr = r.LookupAddr
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+
+	for i := 0; i < 3; i++ {
+		for _, addr := range []string{"192.0.2.1", "192.0.2.9"} {
+			m := testutil.MustMetric("ping",
+				map[string]string{"source": addr},
+				map[string]interface{}{"value": 42},
+				time.Unix(0, 0),
+			)
+			require.NoError(t, plugin.Add(m, &acc))
+		}
+		// Wait for the lookups in progress, so the next metrics are
+		// found in the cache.
+		plugin.wg.Wait()
+	}
+	require.NoError(t, plugin.Stop())
+
+	require.Equal(t, map[string]int{"192.0.2.1": 1, "192.0.2.9": 1}, r.lookups)
+	require.Len(t, acc.GetTelegrafMetrics(), 6)
+}
+
+func TestCacheExpires(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := newCache(time.Minute, func() time.Time {
+		return now
+	})
+
+	c.set("192.0.2.1", "host1.example.org")
+	name, ok := c.get("192.0.2.1")
+	require.True(t, ok)
+	require.Equal(t, "host1.example.org", name)
+
+	now = now.Add(time.Minute)
+	_, ok = c.get("192.0.2.1")
+	require.False(t, ok)
+
+	c.set("192.0.2.2", "host2.example.org")
+	require.Len(t, c.entries, 1)
+}
+
+func TestPassThrough(t *testing.T) {
+	plugin := NewReverseDNS()
+	plugin.PassThrough = true
+	plugin.Lookups = []lookup{{Tag: "source", Dest: "source_name"}}
+
+	// The lookup blocks until released, so the metric must be passed on
+	// without waiting for it.
+	release := make(chan struct{})
+	plugin.lookupAddr = func(ctx context.Context, addr string) ([]string, error) {
+		<-release
+		return []string{"host1.example.org."}, nil
+	}
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+
+	newMetric := func() telegraf.Metric {
+		return testutil.MustMetric("ping",
+			map[string]string{"source": "192.0.2.1"},
+			map[string]interface{}{"value": 42},
+			time.Unix(0, 0),
+		)
+	}
+
+	require.NoError(t, plugin.Add(newMetric(), &acc))
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{newMetric()}, acc.GetTelegrafMetrics())
+
+	close(release)
+	plugin.wg.Wait()
+
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Add(newMetric(), &acc))
+	require.NoError(t, plugin.Stop())
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("ping",
+			map[string]string{"source": "192.0.2.1", "source_name": "host1.example.org"},
+			map[string]interface{}{"value": 42},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
+func TestLookupTimeout(t *testing.T) {
+	plugin := NewReverseDNS()
+	plugin.LookupTimeout.Duration = time.Millisecond
+	plugin.Lookups = []lookup{{Tag: "source", Dest: "source_name"}}
+	plugin.lookupAddr = func(ctx context.Context, addr string) ([]string, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+
+	m := testutil.MustMetric("ping",
+		map[string]string{"source": "192.0.2.1"},
+		map[string]interface{}{"value": 42},
+		time.Unix(0, 0),
+	)
+	require.NoError(t, plugin.Add(m.Copy(), &acc))
+	require.NoError(t, plugin.Stop())
+
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{m}, acc.GetTelegrafMetrics())
+}
+
+func TestStartErrors(t *testing.T) {
+	var tests = []struct {
+		name    string
+		lookups []lookup
+	}{
+		{
+			name:    "no tag or field",
+			lookups: []lookup{{Dest: "name"}},
+		},
+		{
+			name:    "tag and field",
+			lookups: []lookup{{Tag: "source", Field: "source"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := NewReverseDNS()
+			plugin.Lookups = tt.lookups
+
+			var acc testutil.Accumulator
+			require.Error(t, plugin.Start(&acc))
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package split
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+const sampleConfig = `
+  ## Tags and fields to split are defined in separate sub-tables.  Only string
+  ## fields are split.
+  [[processors.split.fields]]
+    ## Field to split
+    key = "stats"
+
+    ## Literal separator between the parts, or a regular expression matching
+    ## the separators.  Only one of separator or pattern can be set.
+    separator = ";"
+    # pattern = "\\s*[;,]\\s*"
+
+    ## Names of the parts by position.  Parts without a name, or with an empty
+    ## name, are ignored.
+    # names = ["user", "system", "idle"]
+
+    ## Separator between the name and the value within each part, used
+    ## instead of names for values such as "a=1;b=2".
+    key_value_separator = "="
+
+    ## Prefix added to the names of the parts.
+    # prefix = ""
+
+    ## Where the parts are written, "field" or "tag".  Defaults to the kind of
+    ## the value being split.
+    # destination = "field"
+
+    ## Types of the parts written to fields, one of "string", "integer",
+    ## "unsigned", "float" or "boolean".  Parts not listed are strings.
+    # [processors.split.fields.types]
+    #   a = "integer"
+    #   b = "float"
+
+    ## Remove the value that was split.  Metrics left with no fields are
+    ## dropped.
+    # drop_original = false
+
+  # [[processors.split.tags]]
+  #   key = "position"
+  #   separator = "/"
+  #   names = ["row", "column"]
+`
+
+type Split struct {
+	Tags   []*splitter `toml:"tags"`
+	Fields []*splitter `toml:"fields"`
+}
+
+type splitter struct {
+	Key               string            `toml:"key"`
+	Separator         string            `toml:"separator"`
+	Pattern           string            `toml:"pattern"`
+	Names             []string          `toml:"names"`
+	KeyValueSeparator string            `toml:"key_value_separator"`
+	Prefix            string            `toml:"prefix"`
+	Destination       string            `toml:"destination"`
+	Types             map[string]string `toml:"types"`
+	DropOriginal      bool              `toml:"drop_original"`
+
+	pattern *regexp.Regexp
+}
+
+// part is a named value split from a tag or field.
+type part struct {
+	name  string
+	value string
+}
+
+func (s *Split) SampleConfig() string {
+	return sampleConfig
+}
+
+func (s *Split) Description() string {
+	return "Split tags and string fields into multiple tags or fields"
+}
+
+func (s *Split) Init() error {
+	for _, sp := range s.Tags {
+		if err := sp.init("tag"); err != nil {
+			return err
+		}
+	}
+	for _, sp := range s.Fields {
+		if err := sp.init("field"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sp *splitter) init(source string) error {
+	if sp.Key == "" {
+		return errors.New("key is required")
+	}
+
+	if (sp.Separator == "") == (sp.Pattern == "") {
+		return fmt.Errorf("exactly one of separator or pattern must be set for %q", sp.Key)
+	}
+
+	if sp.Pattern != "" {
+		var err error
+		sp.pattern, err = regexp.Compile(sp.Pattern)
+		if err != nil {
+			return err
+		}
+	}
+
+	if (len(sp.Names) == 0) == (sp.KeyValueSeparator == "") {
+		return fmt.Errorf("exactly one of names or key_value_separator must be set for %q", sp.Key)
+	}
+
+	switch sp.Destination {
+	case "":
+		sp.Destination = source
+	case "tag", "field":
+	default:
+		return fmt.Errorf("invalid destination for %q: %s", sp.Key, sp.Destination)
+	}
+
+	for name, typ := range sp.Types {
+		switch typ {
+		case "string", "integer", "unsigned", "float", "boolean":
+		default:
+			return fmt.Errorf("invalid type for %q: %s", name, typ)
+		}
+	}
+	return nil
+}
+
+func (s *Split) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	out := in[:0]
+	for _, m := range in {
+		for _, sp := range s.Tags {
+			if value, ok := m.GetTag(sp.Key); ok {
+				if sp.DropOriginal {
+					m.RemoveTag(sp.Key)
+				}
+				sp.apply(m, value)
+			}
+		}
+
+		for _, sp := range s.Fields {
+			if value, ok := m.GetField(sp.Key); ok {
+				if value, ok := value.(string); ok {
+					if sp.DropOriginal {
+						m.RemoveField(sp.Key)
+					}
+					sp.apply(m, value)
+				}
+			}
+		}
+
+		// Metrics without fields are invalid, this happens when the only
+		// field is split into tags.
+		if len(m.FieldList()) == 0 {
+			m.Drop()
+			continue
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// apply adds the parts of the value to the metric.
+func (sp *splitter) apply(m telegraf.Metric, value string) {
+	for _, p := range sp.parts(value) {
+		name := sp.Prefix + p.name
+		if sp.Destination == "tag" {
+			if p.value != "" {
+				m.AddTag(name, p.value)
+			}
+			continue
+		}
+
+		v, err := convert(p.value, sp.Types[p.name])
+		if err != nil {
+			log.Printf("D! [processors.split] Could not convert %q of %q: %v", p.name, sp.Key, err)
+			continue
+		}
+		m.AddField(name, v)
+	}
+}
+
+// parts splits the value into named parts.
+func (sp *splitter) parts(value string) []part {
+	var values []string
+	if sp.pattern != nil {
+		values = sp.pattern.Split(value, -1)
+	} else {
+		values = strings.Split(value, sp.Separator)
+	}
+
+	parts := make([]part, 0, len(values))
+	if sp.KeyValueSeparator != "" {
+		for _, v := range values {
+			kv := strings.SplitN(v, sp.KeyValueSeparator, 2)
+			if len(kv) != 2 || kv[0] == "" {
+				continue
+			}
+			parts = append(parts, part{name: kv[0], value: kv[1]})
+		}
+		return parts
+	}
+
+	for i, v := range values {
+		if i >= len(sp.Names) {
+			break
+		}
+		if sp.Names[i] == "" {
+			continue
+		}
+		parts = append(parts, part{name: sp.Names[i], value: v})
+	}
+	return parts
+}
+
+// convert converts the value of a part to the field type, surrounding spaces
+// are ignored for types other than strings.
+func convert(value, typ string) (interface{}, error) {
+	if typ != "" && typ != "string" {
+		value = strings.TrimSpace(value)
+	}
+
+	switch typ {
+	case "integer":
+		return strconv.ParseInt(value, 10, 64)
+	case "unsigned":
+		return strconv.ParseUint(value, 10, 64)
+	case "float":
+		return strconv.ParseFloat(value, 64)
+	case "boolean":
+		return strconv.ParseBool(value)
+	}
+	return value, nil
+}
+
+func init() {
+	processors.Add("split", func() telegraf.Processor {
+		return &Split{}
+	})
+}
@@ -0,0 +1,254 @@
+package split
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplit(t *testing.T) {
+	var tests = []struct {
+		name     string
+		plugin   *Split
+		input    telegraf.Metric
+		expected telegraf.Metric
+	}{
+		{
+			name: "key value pairs",
+			plugin: &Split{
+				Fields: []*splitter{
+					{
+						Key:               "stats",
+						Separator:         ";",
+						KeyValueSeparator: "=",
+						Types:             map[string]string{"a": "integer", "b": "float"},
+					},
+				},
+			},
+			input: testutil.MustMetric("exec",
+				map[string]string{},
+				map[string]interface{}{"stats": "a=1;b=2.5;c=x;invalid"},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("exec",
+				map[string]string{},
+				map[string]interface{}{"stats": "a=1;b=2.5;c=x;invalid", "a": int64(1), "b": 2.5, "c": "x"},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "named parts",
+			plugin: &Split{
+				Fields: []*splitter{
+					{
+						Key:          "value",
+						Separator:    "/",
+						Names:        []string{"user", "", "idle"},
+						Prefix:       "cpu_",
+						Types:        map[string]string{"user": "unsigned", "idle": "unsigned"},
+						DropOriginal: true,
+					},
+				},
+			},
+			input: testutil.MustMetric("exec",
+				map[string]string{},
+				map[string]interface{}{"value": "12/34/56/78"},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("exec",
+				map[string]string{},
+				map[string]interface{}{"cpu_user": uint64(12), "cpu_idle": uint64(56)},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "pattern",
+			plugin: &Split{
+				Fields: []*splitter{
+					{
+						Key:     "value",
+						Pattern: `\s*[;,]\s*`,
+						Names:   []string{"a", "b", "c"},
+						Types:   map[string]string{"c": "boolean"},
+					},
+				},
+			},
+			input: testutil.MustMetric("exec",
+				map[string]string{},
+				map[string]interface{}{"value": "x , y;true"},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("exec",
+				map[string]string{},
+				map[string]interface{}{"value": "x , y;true", "a": "x", "b": "y", "c": true},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "tag to tags",
+			plugin: &Split{
+				Tags: []*splitter{
+					{
+						Key:          "position",
+						Separator:    "/",
+						Names:        []string{"row", "column"},
+						DropOriginal: true,
+					},
+				},
+			},
+			input: testutil.MustMetric("rack",
+				map[string]string{"position": "3/"},
+				map[string]interface{}{"value": 42},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("rack",
+				map[string]string{"row": "3"},
+				map[string]interface{}{"value": 42},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "tag to fields",
+			plugin: &Split{
+				Tags: []*splitter{
+					{
+						Key:         "version",
+						Separator:   ".",
+						Names:       []string{"major", "minor"},
+						Destination: "field",
+						Types:       map[string]string{"major": "integer", "minor": "integer"},
+					},
+				},
+			},
+			input: testutil.MustMetric("app",
+				map[string]string{"version": "1.12"},
+				map[string]interface{}{"value": 42},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("app",
+				map[string]string{"version": "1.12"},
+				map[string]interface{}{"value": 42, "major": int64(1), "minor": int64(12)},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "conversion errors and non string fields",
+			plugin: &Split{
+				Fields: []*splitter{
+					{
+						Key:       "value",
+						Separator: ",",
+						Names:     []string{"a", "b"},
+						Types:     map[string]string{"a": "integer"},
+					},
+					{
+						Key:       "number",
+						Separator: ",",
+						Names:     []string{"c"},
+					},
+				},
+			},
+			input: testutil.MustMetric("exec",
+				map[string]string{},
+				map[string]interface{}{"value": "x,y", "number": 42},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("exec",
+				map[string]string{},
+				map[string]interface{}{"value": "x,y", "number": 42, "b": "y"},
+				time.Unix(0, 0),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.plugin.Init())
+			actual := tt.plugin.Apply(tt.input)
+			testutil.RequireMetricsEqual(t, []telegraf.Metric{tt.expected}, actual)
+		})
+	}
+}
+
+func TestDropOriginalOnlyField(t *testing.T) {
+	plugin := &Split{
+		Fields: []*splitter{
+			{
+				Key:          "path",
+				Separator:    "/",
+				Names:        []string{"service", "endpoint"},
+				Destination:  "tag",
+				DropOriginal: true,
+			},
+		},
+	}
+	require.NoError(t, plugin.Init())
+
+	input := []telegraf.Metric{
+		testutil.MustMetric("request",
+			map[string]string{},
+			map[string]interface{}{"path": "users/login"},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("request",
+			map[string]string{},
+			map[string]interface{}{"path": "users/login", "value": 42},
+			time.Unix(0, 0),
+		),
+	}
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("request",
+			map[string]string{"service": "users", "endpoint": "login"},
+			map[string]interface{}{"value": 42},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, plugin.Apply(input...))
+}
+
+func TestInvalidConfiguration(t *testing.T) {
+	var tests = []struct {
+		name     string
+		splitter *splitter
+	}{
+		{
+			name:     "no key",
+			splitter: &splitter{Separator: ",", Names: []string{"a"}},
+		},
+		{
+			name:     "no separator",
+			splitter: &splitter{Key: "value", Names: []string{"a"}},
+		},
+		{
+			name:     "separator and pattern",
+			splitter: &splitter{Key: "value", Separator: ",", Pattern: ",", Names: []string{"a"}},
+		},
+		{
+			name:     "invalid pattern",
+			splitter: &splitter{Key: "value", Pattern: "(", Names: []string{"a"}},
+		},
+		{
+			name:     "no names",
+			splitter: &splitter{Key: "value", Separator: ","},
+		},
+		{
+			name:     "invalid destination",
+			splitter: &splitter{Key: "value", Separator: ",", Names: []string{"a"}, Destination: "name"},
+		},
+		{
+			name:     "invalid type",
+			splitter: &splitter{Key: "value", Separator: ",", Names: []string{"a"}, Types: map[string]string{"a": "int"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := &Split{Fields: []*splitter{tt.splitter}}
+			require.Error(t, plugin.Init())
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package starlark
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/influxdata/telegraf/metric"
+	"go.starlark.net/starlark"
+)
+
+// newMetric implements the Metric(name) builtin, creating a new metric with
+// the current time.
+func newMetric(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name starlark.String
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &name); err != nil {
+		return nil, err
+	}
+
+	m, err := metric.New(name.GoString(), nil, nil, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &Metric{metric: m}, nil
+}
+
+// deepcopy implements the deepcopy(metric) builtin.  The copy does not take
+// part in the delivery tracking of the original metric.
+func deepcopy(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var sm *Metric
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &sm); err != nil {
+		return nil, err
+	}
+
+	m, err := sm.get()
+	if err != nil {
+		return nil, err
+	}
+
+	dup, err := metric.New(m.Name(), m.Tags(), m.Fields(), m.Time(), m.Type())
+	if err != nil {
+		return nil, err
+	}
+	return &Metric{metric: dup}, nil
+}
+
+// dict is implemented by the mappings of the tags and fields of a metric.
+type dict interface {
+	starlark.IterableMapping
+	starlark.HasSetKey
+	Len() int
+	Delete(key starlark.Value) (starlark.Value, bool, error)
+	Clear() error
+}
+
+var dictMethods = map[string]func(d dict, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error){
+	"clear":      dictClear,
+	"get":        dictGet,
+	"items":      dictItems,
+	"keys":       dictKeys,
+	"pop":        dictPop,
+	"setdefault": dictSetdefault,
+	"update":     dictUpdate,
+	"values":     dictValues,
+}
+
+func dictMethodNames() []string {
+	names := make([]string, 0, len(dictMethods))
+	for name := range dictMethods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// dictMethod returns the method bound to the dict, or nil if there is no
+// method with the name.
+func dictMethod(d dict, name string) starlark.Value {
+	method, ok := dictMethods[name]
+	if !ok {
+		return nil
+	}
+
+	fn := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		return method(b.Receiver().(dict), b, args, kwargs)
+	}
+	return starlark.NewBuiltin(name, fn).BindReceiver(d)
+}
+
+func dictClear(d dict, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+	return starlark.None, d.Clear()
+}
+
+func dictGet(d dict, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var key, dflt starlark.Value
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &key, &dflt); err != nil {
+		return nil, err
+	}
+
+	v, found, err := d.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		return v, nil
+	}
+	if dflt != nil {
+		return dflt, nil
+	}
+	return starlark.None, nil
+}
+
+func dictItems(d dict, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+
+	items := d.Items()
+	values := make([]starlark.Value, 0, len(items))
+	for _, item := range items {
+		values = append(values, item)
+	}
+	return starlark.NewList(values), nil
+}
+
+func dictKeys(d dict, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+
+	items := d.Items()
+	values := make([]starlark.Value, 0, len(items))
+	for _, item := range items {
+		values = append(values, item[0])
+	}
+	return starlark.NewList(values), nil
+}
+
+func dictValues(d dict, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+
+	items := d.Items()
+	values := make([]starlark.Value, 0, len(items))
+	for _, item := range items {
+		values = append(values, item[1])
+	}
+	return starlark.NewList(values), nil
+}
+
+func dictPop(d dict, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var key, dflt starlark.Value
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &key, &dflt); err != nil {
+		return nil, err
+	}
+
+	v, found, err := d.Delete(key)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		return v, nil
+	}
+	if dflt != nil {
+		return dflt, nil
+	}
+	return nil, fmt.Errorf("%s: missing key %s", b.Name(), key)
+}
+
+func dictSetdefault(d dict, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var key, dflt starlark.Value
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 2, &key, &dflt); err != nil {
+		return nil, err
+	}
+
+	v, found, err := d.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		return v, nil
+	}
+	if err := d.SetKey(key, dflt); err != nil {
+		return nil, err
+	}
+	return dflt, nil
+}
+
+// dictUpdate sets the items from a mapping or an iterable of key/value
+// pairs, followed by the keyword arguments.
+func dictUpdate(d dict, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if len(args) > 1 {
+		return nil, fmt.Errorf("%s: got %d arguments, want at most 1", b.Name(), len(args))
+	}
+
+	if len(args) == 1 {
+		switch updates := args[0].(type) {
+		case starlark.IterableMapping:
+			for _, item := range updates.Items() {
+				if err := d.SetKey(item[0], item[1]); err != nil {
+					return nil, err
+				}
+			}
+		case starlark.Iterable:
+			iter := updates.Iterate()
+			defer iter.Done()
+			var pair starlark.Value
+			for i := 0; iter.Next(&pair); i++ {
+				items, ok := pair.(starlark.Indexable)
+				if !ok || items.Len() != 2 {
+					return nil, fmt.Errorf("%s: element #%d is not a key/value pair", b.Name(), i)
+				}
+				if err := d.SetKey(items.Index(0), items.Index(1)); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			return nil, fmt.Errorf("%s: got %s, want iterable", b.Name(), updates.Type())
+		}
+	}
+
+	for _, kwarg := range kwargs {
+		if err := d.SetKey(kwarg[0], kwarg[1]); err != nil {
+			return nil, err
+		}
+	}
+	return starlark.None, nil
+}
+
+// keyIterator iterates over a copy of the keys of a dict.
+type keyIterator struct {
+	keys []starlark.Value
+}
+
+func (i *keyIterator) Next(p *starlark.Value) bool {
+	if len(i.keys) == 0 {
+		return false
+	}
+	*p = i.keys[0]
+	i.keys = i.keys[1:]
+	return true
+}
+
+func (i *keyIterator) Done() {
+}
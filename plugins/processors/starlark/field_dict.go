@@ -0,0 +1,209 @@
+package starlark
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.starlark.net/starlark"
+)
+
+// FieldDict is the Starlark mapping of the fields of a Metric.
+type FieldDict struct {
+	m *Metric
+}
+
+func (d *FieldDict) String() string {
+	var b strings.Builder
+	b.WriteString("{")
+	for i, item := range d.Items() {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(item[0].String())
+		b.WriteString(": ")
+		b.WriteString(item[1].String())
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func (d *FieldDict) Type() string {
+	return "Fields"
+}
+
+func (d *FieldDict) Freeze() {
+	d.m.Freeze()
+}
+
+func (d *FieldDict) Truth() starlark.Bool {
+	return d.Len() != 0
+}
+
+func (d *FieldDict) Hash() (uint32, error) {
+	return 0, errors.New("not hashable")
+}
+
+// AttrNames implements the starlark.HasAttrs interface.
+func (d *FieldDict) AttrNames() []string {
+	return dictMethodNames()
+}
+
+// Attr implements the starlark.HasAttrs interface.
+func (d *FieldDict) Attr(name string) (starlark.Value, error) {
+	return dictMethod(d, name), nil
+}
+
+// Len implements the starlark.Sequence interface.
+func (d *FieldDict) Len() int {
+	metric, err := d.m.get()
+	if err != nil {
+		return 0
+	}
+	return len(metric.FieldList())
+}
+
+// Get implements the starlark.Mapping interface.
+func (d *FieldDict) Get(key starlark.Value) (v starlark.Value, found bool, err error) {
+	metric, err := d.m.get()
+	if err != nil {
+		return nil, false, err
+	}
+
+	k, ok := key.(starlark.String)
+	if !ok {
+		return nil, false, nil
+	}
+
+	value, ok := metric.GetField(k.GoString())
+	if !ok {
+		return nil, false, nil
+	}
+
+	v, err = asStarlarkValue(value)
+	if err != nil {
+		return nil, false, err
+	}
+	return v, true, nil
+}
+
+// SetKey implements the starlark.HasSetKey interface.
+func (d *FieldDict) SetKey(k, v starlark.Value) error {
+	metric, err := d.m.set()
+	if err != nil {
+		return err
+	}
+
+	key, ok := k.(starlark.String)
+	if !ok {
+		return fmt.Errorf("field key must be of type 'str', not '%s'", k.Type())
+	}
+
+	value, err := asGoValue(v)
+	if err != nil {
+		return err
+	}
+
+	metric.AddField(key.GoString(), value)
+	return nil
+}
+
+// Items implements the starlark.IterableMapping interface.
+func (d *FieldDict) Items() []starlark.Tuple {
+	metric, err := d.m.get()
+	if err != nil {
+		return nil
+	}
+
+	items := make([]starlark.Tuple, 0, len(metric.FieldList()))
+	for _, field := range metric.FieldList() {
+		value, err := asStarlarkValue(field.Value)
+		if err != nil {
+			continue
+		}
+		items = append(items, starlark.Tuple{starlark.String(field.Key), value})
+	}
+	return items
+}
+
+// Iterate implements the starlark.Iterable interface.  The keys are copied so
+// the fields may be modified while iterating.
+func (d *FieldDict) Iterate() starlark.Iterator {
+	items := d.Items()
+	keys := make([]starlark.Value, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, item[0])
+	}
+	return &keyIterator{keys: keys}
+}
+
+// Delete removes the field, returning its value if it existed.
+func (d *FieldDict) Delete(k starlark.Value) (v starlark.Value, found bool, err error) {
+	metric, err := d.m.set()
+	if err != nil {
+		return nil, false, err
+	}
+
+	v, found, err = d.Get(k)
+	if found {
+		metric.RemoveField(string(k.(starlark.String)))
+	}
+	return v, found, err
+}
+
+// Clear removes all fields.
+func (d *FieldDict) Clear() error {
+	metric, err := d.m.set()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(metric.FieldList()))
+	for _, field := range metric.FieldList() {
+		keys = append(keys, field.Key)
+	}
+	for _, key := range keys {
+		metric.RemoveField(key)
+	}
+	return nil
+}
+
+// asStarlarkValue converts a field value to a Starlark value.
+func asStarlarkValue(value interface{}) (starlark.Value, error) {
+	switch v := value.(type) {
+	case float64:
+		return starlark.Float(v), nil
+	case int64:
+		return starlark.MakeInt64(v), nil
+	case uint64:
+		return starlark.MakeUint64(v), nil
+	case string:
+		return starlark.String(v), nil
+	case bool:
+		return starlark.Bool(v), nil
+	}
+
+	return nil, fmt.Errorf("invalid field type: %T", value)
+}
+
+// asGoValue converts a Starlark value to a field value.
+func asGoValue(value starlark.Value) (interface{}, error) {
+	switch v := value.(type) {
+	case starlark.Float:
+		return float64(v), nil
+	case starlark.Int:
+		if n, ok := v.Int64(); ok {
+			return n, nil
+		}
+		if n, ok := v.Uint64(); ok {
+			return n, nil
+		}
+		return nil, errors.New("field value is out of range")
+	case starlark.String:
+		return v.GoString(), nil
+	case starlark.Bool:
+		return bool(v), nil
+	}
+
+	return nil, fmt.Errorf("field value must be of type 'float', 'int', 'str' or 'bool', not '%s'", value.Type())
+}
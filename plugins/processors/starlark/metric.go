@@ -0,0 +1,131 @@
+package starlark
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"go.starlark.net/starlark"
+)
+
+// Metric is the Starlark value wrapping a telegraf.Metric.
+type Metric struct {
+	metric telegraf.Metric
+	frozen bool
+}
+
+// get returns the wrapped metric, an error is returned if the metric has
+// already been passed on by a previous call of apply.
+func (m *Metric) get() (telegraf.Metric, error) {
+	if m.metric == nil {
+		return nil, errors.New("metric has already been emitted, use deepcopy to keep a copy")
+	}
+	return m.metric, nil
+}
+
+// set returns the wrapped metric for modification.
+func (m *Metric) set() (telegraf.Metric, error) {
+	if m.frozen {
+		return nil, errors.New("cannot modify frozen metric")
+	}
+	return m.get()
+}
+
+// String returns the Starlark representation of the Metric.
+func (m *Metric) String() string {
+	metric, err := m.get()
+	if err != nil {
+		return "Metric()"
+	}
+
+	var b strings.Builder
+	b.WriteString("Metric(")
+	b.WriteString(starlark.String(metric.Name()).String())
+	b.WriteString(", tags=")
+	b.WriteString((&TagDict{m: m}).String())
+	b.WriteString(", fields=")
+	b.WriteString((&FieldDict{m: m}).String())
+	b.WriteString(", time=")
+	b.WriteString(starlark.MakeInt64(metric.Time().UnixNano()).String())
+	b.WriteString(")")
+	return b.String()
+}
+
+func (m *Metric) Type() string {
+	return "Metric"
+}
+
+func (m *Metric) Freeze() {
+	m.frozen = true
+}
+
+func (m *Metric) Truth() starlark.Bool {
+	return true
+}
+
+func (m *Metric) Hash() (uint32, error) {
+	return 0, errors.New("not hashable")
+}
+
+// AttrNames implements the starlark.HasAttrs interface.
+func (m *Metric) AttrNames() []string {
+	return []string{"name", "tags", "fields", "time"}
+}
+
+// Attr implements the starlark.HasAttrs interface.
+func (m *Metric) Attr(name string) (starlark.Value, error) {
+	metric, err := m.get()
+	if err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case "name":
+		return starlark.String(metric.Name()), nil
+	case "tags":
+		return &TagDict{m: m}, nil
+	case "fields":
+		return &FieldDict{m: m}, nil
+	case "time":
+		return starlark.MakeInt64(metric.Time().UnixNano()), nil
+	default:
+		// Returning nil, nil indicates "no such field or method"
+		return nil, nil
+	}
+}
+
+// SetField implements the starlark.HasSetField interface.
+func (m *Metric) SetField(name string, value starlark.Value) error {
+	metric, err := m.set()
+	if err != nil {
+		return err
+	}
+
+	switch name {
+	case "name":
+		name, ok := value.(starlark.String)
+		if !ok {
+			return fmt.Errorf("invalid type for name: %s", value.Type())
+		}
+		metric.SetName(name.GoString())
+		return nil
+	case "time":
+		ns, ok := value.(starlark.Int)
+		if !ok {
+			return fmt.Errorf("invalid type for time: %s", value.Type())
+		}
+		t, ok := ns.Int64()
+		if !ok {
+			return errors.New("time is out of range")
+		}
+		metric.SetTime(time.Unix(0, t))
+		return nil
+	case "tags", "fields":
+		return fmt.Errorf("cannot set %s, modify its items instead", name)
+	default:
+		return starlark.NoSuchAttrError(
+			fmt.Sprintf("cannot assign to field '%s'", name))
+	}
+}
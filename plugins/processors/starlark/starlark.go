@@ -0,0 +1,185 @@
+package starlark
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/processors"
+	"go.starlark.net/starlark"
+)
+
+const sampleConfig = `
+  ## The Starlark source can be set as a string in this configuration file, or
+  ## by referencing a file containing the script.  Only one source or script
+  ## should be set at once.
+  ##
+  ## Source of the Starlark script.
+  source = '''
+def apply(metric):
+  return metric
+'''
+
+  ## File containing a Starlark script.
+  # script = "/usr/local/bin/myscript.star"
+`
+
+type Starlark struct {
+	Source string `toml:"source"`
+	Script string `toml:"script"`
+
+	thread    *starlark.Thread
+	applyFunc *starlark.Function
+}
+
+func (s *Starlark) SampleConfig() string {
+	return sampleConfig
+}
+
+func (s *Starlark) Description() string {
+	return "Process metrics using a Starlark script"
+}
+
+func (s *Starlark) Start(acc telegraf.Accumulator) error {
+	if s.Source == "" && s.Script == "" {
+		return errors.New("one of source or script must be set")
+	}
+	if s.Source != "" && s.Script != "" {
+		return errors.New("only one of source or script can be set")
+	}
+
+	s.thread = &starlark.Thread{
+		Print: func(_ *starlark.Thread, msg string) {
+			log.Printf("I! [processors.starlark] %s", msg)
+		},
+		// Loading of other modules is not allowed, so the thread has no
+		// Load function.
+	}
+
+	builtins := starlark.StringDict{
+		"Metric":   starlark.NewBuiltin("Metric", newMetric),
+		"deepcopy": starlark.NewBuiltin("deepcopy", deepcopy),
+		// The state is kept between calls to apply, unlike the globals of
+		// the script which are frozen once it has been executed.
+		"state": starlark.NewDict(0),
+	}
+
+	var src interface{}
+	filename := s.Script
+	if s.Source != "" {
+		filename = "processor.starlark"
+		src = s.Source
+	}
+
+	globals, err := starlark.ExecFile(s.thread, filename, src, builtins)
+	if err != nil {
+		logError(err)
+		return err
+	}
+
+	apply, ok := globals["apply"]
+	if !ok {
+		return errors.New("apply is not defined")
+	}
+
+	s.applyFunc, ok = apply.(*starlark.Function)
+	if !ok {
+		return errors.New("apply is not a function")
+	}
+
+	if s.applyFunc.NumParams() != 1 {
+		return errors.New("apply function must take one parameter")
+	}
+
+	return nil
+}
+
+func (s *Starlark) Add(metric telegraf.Metric, acc telegraf.Accumulator) error {
+	sm := &Metric{metric: metric}
+	// The metric is only accessible from the script during the call.
+	defer func() {
+		sm.metric = nil
+	}()
+
+	rv, err := starlark.Call(s.thread, s.applyFunc, starlark.Tuple{sm}, nil)
+	if err != nil {
+		logError(err)
+		return err
+	}
+
+	results, err := asMetrics(rv)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		if _, err := result.get(); err != nil {
+			return err
+		}
+	}
+
+	// Once passed on the metrics are no longer accessible from the script,
+	// for example if they were saved in the state.
+	emitted := make(map[*Metric]bool, len(results))
+	for _, result := range results {
+		m := result.metric
+		if emitted[result] {
+			m = m.Copy()
+		}
+		emitted[result] = true
+		acc.AddMetric(m)
+	}
+
+	if !emitted[sm] {
+		metric.Drop()
+	}
+
+	for result := range emitted {
+		result.metric = nil
+	}
+	return nil
+}
+
+func (s *Starlark) Stop() error {
+	return nil
+}
+
+// asMetrics returns the metrics returned by apply, which may be a single
+// metric, a list or tuple of metrics, or None.
+func asMetrics(rv starlark.Value) ([]*Metric, error) {
+	switch rv := rv.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case *Metric:
+		return []*Metric{rv}, nil
+	case starlark.Indexable:
+		metrics := make([]*Metric, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			m, ok := rv.Index(i).(*Metric)
+			if !ok {
+				return nil, fmt.Errorf("apply returned %s in element #%d, want Metric", rv.Index(i).Type(), i)
+			}
+			metrics = append(metrics, m)
+		}
+		return metrics, nil
+	default:
+		return nil, fmt.Errorf("apply returned %s, want Metric, list of Metric or None", rv.Type())
+	}
+}
+
+// logError logs the Starlark backtrace of an error in the script.
+func logError(err error) {
+	if err, ok := err.(*starlark.EvalError); ok {
+		for _, line := range strings.Split(err.Backtrace(), "\n") {
+			log.Printf("E! [processors.starlark] %s", line)
+		}
+	}
+}
+
+func init() {
+	processors.AddStreaming("starlark", func() telegraf.StreamingProcessor {
+		return &Starlark{}
+	})
+}
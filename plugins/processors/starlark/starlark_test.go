@@ -0,0 +1,460 @@
+package starlark
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	var tests = []struct {
+		name     string
+		source   string
+		input    []telegraf.Metric
+		expected []telegraf.Metric
+	}{
+		{
+			name: "passthrough",
+			source: `
+def apply(metric):
+	return metric
+`,
+			input: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "example.org"},
+					map[string]interface{}{"time_idle": 42},
+					time.Unix(0, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "example.org"},
+					map[string]interface{}{"time_idle": 42},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "drop metric",
+			source: `
+def apply(metric):
+	return None
+`,
+			input: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{},
+					map[string]interface{}{"time_idle": 42},
+					time.Unix(0, 0),
+				),
+			},
+			expected: []telegraf.Metric{},
+		},
+		{
+			name: "modify name and time",
+			source: `
+def apply(metric):
+	metric.name = "host_" + metric.name
+	metric.time = metric.time + 1000000000
+	return metric
+`,
+			input: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{},
+					map[string]interface{}{"time_idle": 42},
+					time.Unix(0, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("host_cpu",
+					map[string]string{},
+					map[string]interface{}{"time_idle": 42},
+					time.Unix(1, 0),
+				),
+			},
+		},
+		{
+			name: "compute ratio of fields",
+			source: `
+def apply(metric):
+	metric.fields["ratio"] = metric.fields["used"] / metric.fields["total"]
+	return metric
+`,
+			input: []telegraf.Metric{
+				testutil.MustMetric("mem",
+					map[string]string{},
+					map[string]interface{}{"used": 25, "total": 100},
+					time.Unix(0, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("mem",
+					map[string]string{},
+					map[string]interface{}{"used": 25, "total": 100, "ratio": 0.25},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "set tag from other tags",
+			source: `
+def apply(metric):
+	if metric.tags.get("region") == "us" and "prod" in metric.tags.values():
+		metric.tags["alert"] = "page"
+	metric.tags.pop("env")
+	return metric
+`,
+			input: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{"region": "us", "env": "prod"},
+					map[string]interface{}{"time_idle": 42},
+					time.Unix(0, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{"region": "us", "alert": "page"},
+					map[string]interface{}{"time_idle": 42},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "drop contradictory values",
+			source: `
+def apply(metric):
+	if metric.fields["used"] > metric.fields["total"]:
+		return None
+	return metric
+`,
+			input: []telegraf.Metric{
+				testutil.MustMetric("mem",
+					map[string]string{},
+					map[string]interface{}{"used": 200, "total": 100},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("mem",
+					map[string]string{},
+					map[string]interface{}{"used": 50, "total": 100},
+					time.Unix(0, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("mem",
+					map[string]string{},
+					map[string]interface{}{"used": 50, "total": 100},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "iterate and modify fields",
+			source: `
+def apply(metric):
+	for k, v in metric.fields.items():
+		if type(v) == "int":
+			metric.fields[k] = float(v)
+	for k in metric.fields:
+		if k.startswith("tmp_"):
+			metric.fields.pop(k)
+	return metric
+`,
+			input: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{},
+					map[string]interface{}{"a": 1, "tmp_b": "x", "c": true},
+					time.Unix(0, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{},
+					map[string]interface{}{"a": 1.0, "c": true},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "return multiple metrics",
+			source: `
+def apply(metric):
+	copy = deepcopy(metric)
+	copy.name = "copy"
+	new = Metric("new")
+	new.tags.update(host="example.org")
+	new.fields["value"] = 1
+	new.time = 0
+	return [metric, copy, new]
+`,
+			input: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{},
+					map[string]interface{}{"time_idle": 42},
+					time.Unix(0, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{},
+					map[string]interface{}{"time_idle": 42},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("copy",
+					map[string]string{},
+					map[string]interface{}{"time_idle": 42},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("new",
+					map[string]string{"host": "example.org"},
+					map[string]interface{}{"value": 1},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "keep state between calls",
+			source: `
+def apply(metric):
+	count = state.get("count", 0) + 1
+	state["count"] = count
+	metric.fields["count"] = count
+	return metric
+`,
+			input: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{},
+					map[string]interface{}{},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("cpu",
+					map[string]string{},
+					map[string]interface{}{},
+					time.Unix(0, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{},
+					map[string]interface{}{"count": 1},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("cpu",
+					map[string]string{},
+					map[string]interface{}{"count": 2},
+					time.Unix(0, 0),
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := &Starlark{Source: tt.source}
+
+			var acc testutil.Accumulator
+			require.NoError(t, plugin.Start(&acc))
+
+			for _, m := range tt.input {
+				require.NoError(t, plugin.Add(m, &acc))
+			}
+			require.NoError(t, plugin.Stop())
+
+			testutil.RequireMetricsEqual(t, tt.expected, acc.GetTelegrafMetrics())
+		})
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	var tests = []struct {
+		name   string
+		source string
+	}{
+		{
+			name: "runtime error",
+			source: `
+def apply(metric):
+	return metric.fields["missing"]
+`,
+		},
+		{
+			name: "invalid return type",
+			source: `
+def apply(metric):
+	return "cpu"
+`,
+		},
+		{
+			name: "invalid field type",
+			source: `
+def apply(metric):
+	metric.fields["x"] = [1, 2]
+	return metric
+`,
+		},
+		{
+			name: "invalid tag type",
+			source: `
+def apply(metric):
+	metric.tags["x"] = 1
+	return metric
+`,
+		},
+		{
+			name: "metric kept from previous call",
+			source: `
+def apply(metric):
+	last = state.get("last")
+	state["last"] = metric
+	if last != None:
+		return last
+	return metric
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := &Starlark{Source: tt.source}
+
+			var acc testutil.Accumulator
+			require.NoError(t, plugin.Start(&acc))
+
+			m := testutil.MustMetric("cpu",
+				map[string]string{},
+				map[string]interface{}{"time_idle": 42},
+				time.Unix(0, 0),
+			)
+
+			err := plugin.Add(m, &acc)
+			if err == nil {
+				err = plugin.Add(m.Copy(), &acc)
+			}
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestStartErrors(t *testing.T) {
+	var tests = []struct {
+		name   string
+		plugin *Starlark
+	}{
+		{
+			name:   "no source",
+			plugin: &Starlark{},
+		},
+		{
+			name: "source and script",
+			plugin: &Starlark{
+				Source: "def apply(metric):\n\treturn metric\n",
+				Script: "testdata/script.star",
+			},
+		},
+		{
+			name:   "syntax error",
+			plugin: &Starlark{Source: "def apply(metric)\n"},
+		},
+		{
+			name:   "no apply function",
+			plugin: &Starlark{Source: "x = 1\n"},
+		},
+		{
+			name:   "apply with wrong parameters",
+			plugin: &Starlark{Source: "def apply(a, b):\n\treturn a\n"},
+		},
+		{
+			name:   "load is not allowed",
+			plugin: &Starlark{Source: "load('other.star', 'x')\ndef apply(metric):\n\treturn metric\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var acc testutil.Accumulator
+			require.Error(t, tt.plugin.Start(&acc))
+		})
+	}
+}
+
+func TestTracking(t *testing.T) {
+	var tests = []struct {
+		name   string
+		source string
+	}{
+		{
+			name:   "returned",
+			source: "def apply(metric):\n\treturn metric\n",
+		},
+		{
+			name:   "dropped",
+			source: "def apply(metric):\n\treturn None\n",
+		},
+		{
+			name:   "returned twice",
+			source: "def apply(metric):\n\treturn [metric, metric]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := &Starlark{Source: tt.source}
+
+			acc := &metricAccumulator{}
+			require.NoError(t, plugin.Start(acc))
+
+			var delivered bool
+			m, _ := metric.WithTracking(
+				testutil.MustMetric("cpu",
+					map[string]string{},
+					map[string]interface{}{"time_idle": 42},
+					time.Unix(0, 0),
+				),
+				func(telegraf.DeliveryInfo) {
+					delivered = true
+				},
+			)
+			require.NoError(t, plugin.Add(m, acc))
+
+			for _, m := range acc.metrics {
+				m.Accept()
+			}
+			require.True(t, delivered)
+		})
+	}
+}
+
+// metricAccumulator keeps the metrics added so their tracking can be checked.
+type metricAccumulator struct {
+	testutil.Accumulator
+	metrics []telegraf.Metric
+}
+
+func (a *metricAccumulator) AddMetric(m telegraf.Metric) {
+	a.metrics = append(a.metrics, m)
+}
+
+func TestScript(t *testing.T) {
+	plugin := &Starlark{Script: "testdata/ratio.star"}
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+
+	m := testutil.MustMetric("mem",
+		map[string]string{},
+		map[string]interface{}{"used": 25, "total": 100},
+		time.Unix(0, 0),
+	)
+	require.NoError(t, plugin.Add(m, &acc))
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("mem",
+			map[string]string{},
+			map[string]interface{}{"used": 25, "total": 100, "used_percent": 25.0},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
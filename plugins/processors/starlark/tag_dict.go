@@ -0,0 +1,168 @@
+package starlark
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.starlark.net/starlark"
+)
+
+// TagDict is the Starlark mapping of the tags of a Metric.
+type TagDict struct {
+	m *Metric
+}
+
+func (d *TagDict) String() string {
+	metric, err := d.m.get()
+	if err != nil {
+		return "{}"
+	}
+
+	var b strings.Builder
+	b.WriteString("{")
+	for i, tag := range metric.TagList() {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(starlark.String(tag.Key).String())
+		b.WriteString(": ")
+		b.WriteString(starlark.String(tag.Value).String())
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func (d *TagDict) Type() string {
+	return "Tags"
+}
+
+func (d *TagDict) Freeze() {
+	d.m.Freeze()
+}
+
+func (d *TagDict) Truth() starlark.Bool {
+	return d.Len() != 0
+}
+
+func (d *TagDict) Hash() (uint32, error) {
+	return 0, errors.New("not hashable")
+}
+
+// AttrNames implements the starlark.HasAttrs interface.
+func (d *TagDict) AttrNames() []string {
+	return dictMethodNames()
+}
+
+// Attr implements the starlark.HasAttrs interface.
+func (d *TagDict) Attr(name string) (starlark.Value, error) {
+	return dictMethod(d, name), nil
+}
+
+// Len implements the starlark.Sequence interface.
+func (d *TagDict) Len() int {
+	metric, err := d.m.get()
+	if err != nil {
+		return 0
+	}
+	return len(metric.TagList())
+}
+
+// Get implements the starlark.Mapping interface.
+func (d *TagDict) Get(key starlark.Value) (v starlark.Value, found bool, err error) {
+	metric, err := d.m.get()
+	if err != nil {
+		return nil, false, err
+	}
+
+	k, ok := key.(starlark.String)
+	if !ok {
+		return nil, false, nil
+	}
+
+	value, ok := metric.GetTag(k.GoString())
+	if !ok {
+		return nil, false, nil
+	}
+	return starlark.String(value), true, nil
+}
+
+// SetKey implements the starlark.HasSetKey interface.
+func (d *TagDict) SetKey(k, v starlark.Value) error {
+	metric, err := d.m.set()
+	if err != nil {
+		return err
+	}
+
+	key, ok := k.(starlark.String)
+	if !ok {
+		return fmt.Errorf("tag key must be of type 'str', not '%s'", k.Type())
+	}
+
+	value, ok := v.(starlark.String)
+	if !ok {
+		return fmt.Errorf("tag value must be of type 'str', not '%s'", v.Type())
+	}
+
+	metric.AddTag(key.GoString(), value.GoString())
+	return nil
+}
+
+// Items implements the starlark.IterableMapping interface.
+func (d *TagDict) Items() []starlark.Tuple {
+	metric, err := d.m.get()
+	if err != nil {
+		return nil
+	}
+
+	items := make([]starlark.Tuple, 0, len(metric.TagList()))
+	for _, tag := range metric.TagList() {
+		items = append(items, starlark.Tuple{
+			starlark.String(tag.Key),
+			starlark.String(tag.Value),
+		})
+	}
+	return items
+}
+
+// Iterate implements the starlark.Iterable interface.  The keys are copied so
+// the tags may be modified while iterating.
+func (d *TagDict) Iterate() starlark.Iterator {
+	items := d.Items()
+	keys := make([]starlark.Value, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, item[0])
+	}
+	return &keyIterator{keys: keys}
+}
+
+// Delete removes the tag, returning its value if it existed.
+func (d *TagDict) Delete(k starlark.Value) (v starlark.Value, found bool, err error) {
+	metric, err := d.m.set()
+	if err != nil {
+		return nil, false, err
+	}
+
+	v, found, err = d.Get(k)
+	if found {
+		metric.RemoveTag(string(k.(starlark.String)))
+	}
+	return v, found, err
+}
+
+// Clear removes all tags.
+func (d *TagDict) Clear() error {
+	metric, err := d.m.set()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(metric.TagList()))
+	for _, tag := range metric.TagList() {
+		keys = append(keys, tag.Key)
+	}
+	for _, key := range keys {
+		metric.RemoveTag(key)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package processors
+
+import (
+	"io"
+
+	"github.com/influxdata/telegraf"
+)
+
+// Unwrapper is implemented by a telegraf.StreamingProcessor that wraps
+// another plugin, so that the plugin configuration can be applied to the
+// wrapped value.
+type Unwrapper interface {
+	Unwrap() telegraf.Processor
+}
+
+// Initializer is implemented by a telegraf.Processor that validates its
+// configuration before it is started.  An error returned by Init stops the
+// processor from starting.
+type Initializer interface {
+	Init() error
+}
+
+// NewStreamingProcessorFromProcessor adapts a telegraf.Processor so that it
+// can be run as a telegraf.StreamingProcessor.  If the processor implements
+// io.Closer it is closed when the processor is stopped.
+func NewStreamingProcessorFromProcessor(p telegraf.Processor) telegraf.StreamingProcessor {
+	return &streamingProcessor{processor: p}
+}
+
+type streamingProcessor struct {
+	processor telegraf.Processor
+}
+
+func (sp *streamingProcessor) SampleConfig() string {
+	return sp.processor.SampleConfig()
+}
+
+func (sp *streamingProcessor) Description() string {
+	return sp.processor.Description()
+}
+
+func (sp *streamingProcessor) Start(acc telegraf.Accumulator) error {
+	if p, ok := sp.processor.(Initializer); ok {
+		return p.Init()
+	}
+	return nil
+}
+
+func (sp *streamingProcessor) Add(m telegraf.Metric, acc telegraf.Accumulator) error {
+	for _, m := range sp.processor.Apply(m) {
+		acc.AddMetric(m)
+	}
+	return nil
+}
+
+func (sp *streamingProcessor) Stop() error {
+	if c, ok := sp.processor.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Unwrap returns the wrapped telegraf.Processor.
+func (sp *streamingProcessor) Unwrap() telegraf.Processor {
+	return sp.processor
+}
@@ -0,0 +1,100 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+const sampleConfig = `
+  ## Where the output of the template is written, one of "tag", "field" or
+  ## "measurement".
+  destination = "tag"
+
+  ## Key of the tag or field to set, not used with the "measurement"
+  ## destination.
+  key = "service"
+
+  ## Go text/template to evaluate for each metric.  The metric is available
+  ## with the methods:
+  ##   .Name          : the measurement name
+  ##   .Tag "key"     : the value of a tag, empty if missing
+  ##   .Field "key"   : the value of a field, empty if missing
+  ##   .Tags, .Fields : all tags or fields
+  ##   .Time          : the metric time
+  template = '{{ .Tag "namespace" }}/{{ .Tag "pod" }}'
+`
+
+type Template struct {
+	Destination string `toml:"destination"`
+	Key         string `toml:"key"`
+	Template    string `toml:"template"`
+
+	tmpl *template.Template
+}
+
+func (t *Template) SampleConfig() string {
+	return sampleConfig
+}
+
+func (t *Template) Description() string {
+	return "Set a tag, field or the measurement name from a template"
+}
+
+func (t *Template) Init() error {
+	switch t.Destination {
+	case "tag", "field":
+		if t.Key == "" {
+			return fmt.Errorf("key is required for the %s destination", t.Destination)
+		}
+	case "measurement":
+	default:
+		return fmt.Errorf("invalid destination: %s", t.Destination)
+	}
+
+	if t.Template == "" {
+		return errors.New("template is required")
+	}
+
+	var err error
+	t.tmpl, err = template.New("template").Option("missingkey=zero").Parse(t.Template)
+	return err
+}
+
+func (t *Template) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	for _, m := range in {
+		var b strings.Builder
+		if err := t.tmpl.Execute(&b, &TemplateMetric{metric: m}); err != nil {
+			log.Printf("E! [processors.template] Could not execute template: %v", err)
+			continue
+		}
+		value := b.String()
+
+		// Empty tag values and measurement names are not valid, so the
+		// metric is left unmodified.
+		switch t.Destination {
+		case "tag":
+			if value != "" {
+				m.AddTag(t.Key, value)
+			}
+		case "field":
+			m.AddField(t.Key, value)
+		case "measurement":
+			if value != "" {
+				m.SetName(value)
+			}
+		}
+	}
+	return in
+}
+
+func init() {
+	processors.Add("template", func() telegraf.Processor {
+		return &Template{}
+	})
+}
@@ -0,0 +1,47 @@
+package template
+
+import (
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+// TemplateMetric is the value passed to templates, giving read access to the
+// metric.
+type TemplateMetric struct {
+	metric telegraf.Metric
+}
+
+// Name returns the measurement name.
+func (m *TemplateMetric) Name() string {
+	return m.metric.Name()
+}
+
+// Tag returns the value of the tag, or an empty string if the metric does not
+// have the tag.
+func (m *TemplateMetric) Tag(key string) string {
+	value, _ := m.metric.GetTag(key)
+	return value
+}
+
+// Field returns the value of the field, or nil if the metric does not have the
+// field.
+func (m *TemplateMetric) Field(key string) interface{} {
+	value, _ := m.metric.GetField(key)
+	return value
+}
+
+// Tags returns the tags of the metric.
+func (m *TemplateMetric) Tags() map[string]string {
+	return m.metric.Tags()
+}
+
+// Fields returns the fields of the metric.
+func (m *TemplateMetric) Fields() map[string]interface{} {
+	return m.metric.Fields()
+}
+
+// Time returns the time of the metric.
+func (m *TemplateMetric) Time() time.Time {
+	return m.metric.Time()
+}
@@ -0,0 +1,147 @@
+package template
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate(t *testing.T) {
+	var tests = []struct {
+		name     string
+		plugin   *Template
+		input    telegraf.Metric
+		expected telegraf.Metric
+	}{
+		{
+			name: "tag from tags",
+			plugin: &Template{
+				Destination: "tag",
+				Key:         "service",
+				Template:    `{{ .Tag "namespace" }}/{{ .Tag "pod" }}`,
+			},
+			input: testutil.MustMetric("kube",
+				map[string]string{"namespace": "web", "pod": "nginx-1"},
+				map[string]interface{}{"value": 42},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("kube",
+				map[string]string{"namespace": "web", "pod": "nginx-1", "service": "web/nginx-1"},
+				map[string]interface{}{"value": 42},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "field from name and tag",
+			plugin: &Template{
+				Destination: "field",
+				Key:         "description",
+				Template:    `{{ .Name }} on {{ .Tag "host" }} is {{ .Field "value" }}`,
+			},
+			input: testutil.MustMetric("cpu",
+				map[string]string{"host": "example.org"},
+				map[string]interface{}{"value": 42},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("cpu",
+				map[string]string{"host": "example.org"},
+				map[string]interface{}{"value": 42, "description": "cpu on example.org is 42"},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "measurement name",
+			plugin: &Template{
+				Destination: "measurement",
+				Template:    `{{ .Name }}_{{ .Time.UTC.Format "2006" }}`,
+			},
+			input: testutil.MustMetric("cpu",
+				map[string]string{},
+				map[string]interface{}{"value": 42},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("cpu_1970",
+				map[string]string{},
+				map[string]interface{}{"value": 42},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "range over tags",
+			plugin: &Template{
+				Destination: "tag",
+				Key:         "all",
+				Template:    `{{ range $k, $v := .Tags }}{{ $k }}={{ $v }};{{ end }}`,
+			},
+			input: testutil.MustMetric("cpu",
+				map[string]string{"b": "2", "a": "1"},
+				map[string]interface{}{"value": 42},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("cpu",
+				map[string]string{"a": "1", "b": "2", "all": "a=1;b=2;"},
+				map[string]interface{}{"value": 42},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "empty tag is not set",
+			plugin: &Template{
+				Destination: "tag",
+				Key:         "service",
+				Template:    `{{ .Tag "missing" }}`,
+			},
+			input: testutil.MustMetric("cpu",
+				map[string]string{},
+				map[string]interface{}{"value": 42},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("cpu",
+				map[string]string{},
+				map[string]interface{}{"value": 42},
+				time.Unix(0, 0),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.plugin.Init())
+			actual := tt.plugin.Apply(tt.input)
+			testutil.RequireMetricsEqual(t, []telegraf.Metric{tt.expected}, actual)
+		})
+	}
+}
+
+func TestInvalidConfiguration(t *testing.T) {
+	var tests = []struct {
+		name   string
+		plugin *Template
+	}{
+		{
+			name:   "invalid destination",
+			plugin: &Template{Destination: "time", Template: "x"},
+		},
+		{
+			name:   "missing key",
+			plugin: &Template{Destination: "tag", Template: "x"},
+		},
+		{
+			name:   "missing template",
+			plugin: &Template{Destination: "measurement"},
+		},
+		{
+			name:   "invalid template",
+			plugin: &Template{Destination: "tag", Key: "x", Template: "{{ .Name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Error(t, tt.plugin.Init())
+		})
+	}
+}
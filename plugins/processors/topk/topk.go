@@ -21,9 +21,13 @@ type TopK struct {
 	Fields             []string
 	Aggregation        string
 	Bottomk            bool
-	AddGroupByTag      string   `toml:"add_groupby_tag"`
-	AddRankFields      []string `toml:"add_rank_fields"`
-	AddAggregateFields []string `toml:"add_aggregate_fields"`
+	AddGroupByTag      string            `toml:"add_groupby_tag"`
+	AddRankFields      []string          `toml:"add_rank_fields"`
+	AddAggregateFields []string          `toml:"add_aggregate_fields"`
+	KPerField          map[string]int    `toml:"k_per_field"`
+	AddOtherMetric     bool              `toml:"add_other_metric"`
+	OtherName          string            `toml:"other_name"`
+	OtherTags          map[string]string `toml:"other_tags"`
 
 	cache           map[string][]telegraf.Metric
 	tagsGlobs       filter.Filter
@@ -99,6 +103,27 @@ var sampleConfig = `
   ## The name of the field will be set to the name of the aggregation field,
   ## suffixed with the string '_topk_aggregate'
   # add_aggregate_fields = []
+
+  ## Emit an additional metric holding, for each of the fields, the sum of
+  ## the values of all the groups outside the top k of that field. This keeps
+  ## the totals over each period correct when only the top k series are
+  ## stored. Requires the "sum" aggregation. The metric is emitted once per
+  ## period with the latest timestamp of the groups it covers
+  # add_other_metric = false
+
+  ## Measurement name of the additional metric. By default the name shared
+  ## by all the groups not returned is used, or "topk_other" if they differ
+  # other_name = ""
+
+  ## Tags added to the additional metric, on top of the tags shared by all
+  ## the groups not returned
+  # [processors.topk.other_tags]
+  #   process_name = "other"
+
+  ## Number of top metrics to return for specific fields, overriding k
+  # [processors.topk.k_per_field]
+  #   cpu_usage = 5
+  #   memory_rss = 3
 `
 
 type MetricAggregation struct {
@@ -133,6 +158,14 @@ func (t *TopK) Reset() {
 	t.lastAggregation = time.Now()
 }
 
+func (t *TopK) Init() error {
+	// The sum of other aggregations, such as means, has no meaning
+	if t.AddOtherMetric && t.Aggregation != "sum" {
+		return fmt.Errorf("add_other_metric requires the 'sum' aggregation, not '%s'", t.Aggregation)
+	}
+	return nil
+}
+
 func (t *TopK) Description() string {
 	return "Print all metrics that pass through this filter."
 }
@@ -281,13 +314,15 @@ func (t *TopK) push() []telegraf.Metric {
 
 	// Get the top K metrics for each field and add them to the return value
 	addedKeys := make(map[string]bool)
+	topKeys := make(map[string]map[string]bool, len(t.Fields))
 	for _, field := range t.Fields {
+		topKeys[field] = make(map[string]bool)
 
 		// Sort the aggregations
 		sortMetrics(aggregations, field, t.Bottomk)
 
 		// Create a one dimensional list with the top K metrics of each key
-		for i, ag := range aggregations[0:min(t.K, len(aggregations))] {
+		for i, ag := range aggregations[0:min(t.getK(field), len(aggregations))] {
 			// Check whether of not we need to add fields of tags to the selected metrics
 			if len(t.aggFieldSet) != 0 || len(t.rankFieldSet) != 0 || t.AddGroupByTag != "" {
 				for _, m := range t.cache[ag.groupbykey] {
@@ -305,6 +340,8 @@ func (t *TopK) push() []telegraf.Metric {
 				}
 			}
 
+			topKeys[field][ag.groupbykey] = true
+
 			// Add metrics if we have not already appended them to the return value
 			_, ok := addedKeys[ag.groupbykey]
 			if !ok {
@@ -314,9 +351,15 @@ func (t *TopK) push() []telegraf.Metric {
 		}
 	}
 
+	// Aggregate the groups that were not returned if requested
+	var other telegraf.Metric
+	if t.AddOtherMetric {
+		other = t.otherMetric(aggregations, topKeys)
+	}
+
 	t.Reset()
 
-	result := make([]telegraf.Metric, 0, len(ret))
+	result := make([]telegraf.Metric, 0, len(ret)+1)
 	for _, m := range ret {
 		copy, err := metric.New(m.Name(), m.Tags(), m.Fields(), m.Time(), m.Type())
 		if err != nil {
@@ -324,10 +367,94 @@ func (t *TopK) push() []telegraf.Metric {
 		}
 		result = append(result, copy)
 	}
+	if other != nil {
+		result = append(result, other)
+	}
 
 	return result
 }
 
+// Returns the number of top metrics to return for the given field
+func (t *TopK) getK(field string) int {
+	if k, ok := t.KPerField[field]; ok {
+		return k
+	}
+	return t.K
+}
+
+// Generates a metric holding, for each field, the sum of the aggregations of
+// the groups outside the top k of that field, which is the sum of their values
+// as only the "sum" aggregation is allowed. Returns nil if all the groups are
+// in the top k of every field
+func (t *TopK) otherMetric(aggregations []MetricAggregation, topKeys map[string]map[string]bool) telegraf.Metric {
+	fields := make(map[string]interface{})
+	var tags map[string]string
+	var name string
+	var timestamp time.Time
+	sameName := true
+	found := false
+
+	for _, ag := range aggregations {
+		other := false
+		for _, field := range t.Fields {
+			if topKeys[field][ag.groupbykey] {
+				continue
+			}
+			if val, ok := ag.values[field]; ok {
+				sum, _ := fields[field].(float64)
+				fields[field] = sum + val
+				other = true
+			}
+		}
+		if !other {
+			continue
+		}
+
+		for _, m := range t.cache[ag.groupbykey] {
+			if !found {
+				name = m.Name()
+				tags = m.Tags()
+				timestamp = m.Time()
+				found = true
+				continue
+			}
+
+			// Only keep the name and tags shared by all the metrics
+			if m.Name() != name {
+				sameName = false
+			}
+			for k, v := range tags {
+				if value, ok := m.GetTag(k); !ok || value != v {
+					delete(tags, k)
+				}
+			}
+			if m.Time().After(timestamp) {
+				timestamp = m.Time()
+			}
+		}
+	}
+
+	if !found || len(fields) == 0 {
+		return nil
+	}
+
+	if t.OtherName != "" {
+		name = t.OtherName
+	} else if !sameName {
+		name = "topk_other"
+	}
+	for k, v := range t.OtherTags {
+		tags[k] = v
+	}
+
+	m, err := metric.New(name, tags, fields, timestamp)
+	if err != nil {
+		log.Printf("E! [processors.topk]: could not create other metric: %v", err)
+		return nil
+	}
+	return m
+}
+
 // Function that generates the aggregation functions
 func (t *TopK) getAggregationFunction(aggOperation string) (func([]telegraf.Metric, []string) map[string]float64, error) {
 	// This is a function aggregates a set of metrics using a given aggregation function
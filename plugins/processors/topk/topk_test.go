@@ -6,6 +6,7 @@ import (
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/testutil"
 )
 
@@ -530,3 +531,162 @@ func TestTopkGroupByKeyTag(t *testing.T) {
 	// Run the test
 	runAndCompare(&topk, input, answer, "GroupByKeyTag test", t)
 }
+
+// KPerField
+func TestTopkKPerField(t *testing.T) {
+
+	// Build the processor
+	var topk TopK
+	topk = *New()
+	topk.Period = createDuration(1)
+	topk.K = 1
+	topk.KPerField = map[string]int{"value": 2}
+	topk.Aggregation = "sum"
+	topk.Fields = []string{"value", "A"}
+	topk.GroupBy = []string{"tag1", "tag3"}
+
+	// Get the input
+	input := deepCopy(MetricsSet2)
+
+	// Generate the answer
+	changeSet := map[int]metricChange{
+		0: {},
+		2: {},
+		4: {},
+		5: {},
+	}
+	answer := generateAns(input, changeSet)
+
+	// Run the test
+	runAndCompare(&topk, input, answer, "KPerField test", t)
+}
+
+// AddOtherMetric
+func TestTopkAddOtherMetric(t *testing.T) {
+
+	// Build the processor
+	var topk TopK
+	topk = *New()
+	topk.Period = createDuration(1)
+	topk.K = 3
+	topk.Aggregation = "sum"
+	topk.GroupBy = []string{"tag1", "tag3"}
+	topk.AddOtherMetric = true
+	topk.OtherTags = map[string]string{"tag1": "other"}
+
+	// Get the input
+	input := deepCopy(MetricsSet2)
+
+	// Generate the answer
+	changeSet := map[int]metricChange{
+		2: {},
+		3: {},
+		4: {},
+		5: {},
+	}
+	answer := generateAns(input, changeSet)
+
+	timestamp := input[0].Time()
+	if input[1].Time().After(timestamp) {
+		timestamp = input[1].Time()
+	}
+	other, _ := metric.New(
+		"metric1",
+		map[string]string{"tag1": "other", "tag4": "EIGHT"},
+		map[string]interface{}{"value": float64(31.31) + float64(59.43)},
+		timestamp,
+	)
+	answer = append(answer, other)
+
+	// Run the test
+	runAndCompare(&topk, input, answer, "AddOtherMetric test", t)
+}
+
+// AddOtherMetric with several fields, each with its own top k
+func TestTopkAddOtherMetricPerField(t *testing.T) {
+
+	// Build the processor
+	var topk TopK
+	topk = *New()
+	topk.Period = createDuration(1)
+	topk.K = 1
+	topk.KPerField = map[string]int{"y": 2}
+	topk.Fields = []string{"x", "y"}
+	topk.Aggregation = "sum"
+	topk.GroupBy = []string{"g"}
+	topk.AddOtherMetric = true
+	topk.OtherTags = map[string]string{"g": "other"}
+
+	// Get the input
+	now := time.Now()
+	newMetric := func(g string, x, y float64) telegraf.Metric {
+		m, _ := metric.New("m1",
+			map[string]string{"g": g},
+			map[string]interface{}{"x": x, "y": y},
+			now,
+		)
+		return m
+	}
+	input := []telegraf.Metric{
+		newMetric("a", 10, 1),
+		newMetric("b", 5, 20),
+		newMetric("c", 1, 2),
+	}
+
+	// Group a is the top 1 of x, groups b and c the top 2 of y. Other holds
+	// the groups outside the top k of each field: b and c for x, a for y
+	other, _ := metric.New("m1",
+		map[string]string{"g": "other"},
+		map[string]interface{}{"x": float64(6), "y": float64(1)},
+		now,
+	)
+	answer := []telegraf.Metric{
+		newMetric("a", 10, 1),
+		newMetric("b", 5, 20),
+		newMetric("c", 1, 2),
+		other,
+	}
+
+	// Run the test
+	runAndCompare(&topk, input, answer, "AddOtherMetric per field test", t)
+}
+
+// AddOtherMetric is only valid with the sum aggregation
+func TestTopkAddOtherMetricRequiresSum(t *testing.T) {
+	for _, aggregation := range []string{"mean", "min", "max"} {
+		topk := New()
+		topk.Aggregation = aggregation
+		topk.AddOtherMetric = true
+		if err := topk.Init(); err == nil {
+			t.Errorf("Expected an error with the '%s' aggregation", aggregation)
+		}
+	}
+
+	topk := New()
+	topk.Aggregation = "sum"
+	topk.AddOtherMetric = true
+	if err := topk.Init(); err != nil {
+		t.Errorf("Unexpected error with the 'sum' aggregation: %v", err)
+	}
+}
+
+// AddOtherMetric when all the groups are returned
+func TestTopkAddOtherMetricAllReturned(t *testing.T) {
+
+	// Build the processor
+	var topk TopK
+	topk = *New()
+	topk.Period = createDuration(1)
+	topk.Aggregation = "sum"
+	topk.GroupBy = []string{"tag1", "tag3"}
+	topk.AddOtherMetric = true
+
+	// Get the input
+	input := deepCopy(MetricsSet2)
+
+	// Generate the answer
+	answer := deepCopy(MetricsSet2)
+
+	// Run the test
+	runAndCompare(&topk, input, answer, "AddOtherMetric all returned test", t)
+}
@@ -0,0 +1,125 @@
+package units
+
+import (
+	"fmt"
+	"strings"
+)
+
+// quantity is the kind of value measured by a unit, only units of the same
+// quantity can be converted to each other.
+type quantity string
+
+const (
+	dataSize    quantity = "data size"
+	duration    quantity = "time"
+	temperature quantity = "temperature"
+	dataRate    quantity = "data rate"
+)
+
+// unit converts values to and from the base unit of its quantity using
+// base = (value + offset) * scale.
+type unit struct {
+	quantity quantity
+	scale    float64
+	offset   float64
+}
+
+func (u unit) toBase(v float64) float64 {
+	return (v + u.offset) * u.scale
+}
+
+func (u unit) fromBase(v float64) float64 {
+	return v/u.scale - u.offset
+}
+
+// sizeUnits have the byte as base unit.
+var sizeUnits = map[string]float64{
+	"bit":  1.0 / 8,
+	"kbit": 1e3 / 8,
+	"Mbit": 1e6 / 8,
+	"Gbit": 1e9 / 8,
+	"Tbit": 1e12 / 8,
+	"B":    1,
+	"kB":   1e3,
+	"MB":   1e6,
+	"GB":   1e9,
+	"TB":   1e12,
+	"PB":   1e15,
+	"KiB":  1 << 10,
+	"MiB":  1 << 20,
+	"GiB":  1 << 30,
+	"TiB":  1 << 40,
+	"PiB":  1 << 50,
+}
+
+// timeUnits have the second as base unit.
+var timeUnits = map[string]float64{
+	"ns":  1e-9,
+	"us":  1e-6,
+	"ms":  1e-3,
+	"s":   1,
+	"min": 60,
+	"h":   3600,
+	"d":   86400,
+	// Jiffies as reported by the kernel to user space, which always uses
+	// a rate of 100 per second.
+	"jiffies": 1e-2,
+}
+
+// temperatureUnits have the degree Celsius as base unit.
+var temperatureUnits = map[string]unit{
+	"C": {quantity: temperature, scale: 1},
+	"F": {quantity: temperature, scale: 5.0 / 9, offset: -32},
+	"K": {quantity: temperature, scale: 1, offset: -273.15},
+}
+
+// rateAliases are the common names of data rate units.
+var rateAliases = map[string]string{
+	"bps":  "bit/s",
+	"kbps": "kbit/s",
+	"Mbps": "Mbit/s",
+	"Gbps": "Gbit/s",
+}
+
+// parseUnit returns the unit with the name.  Pages are converted using the
+// page size in bytes, and data rates are written as a data size unit per time
+// unit, such as "MiB/s".
+func parseUnit(name string, pageSize int64) (unit, error) {
+	if alias, ok := rateAliases[name]; ok {
+		name = alias
+	}
+
+	if scale, ok := sizeScale(name, pageSize); ok {
+		return unit{quantity: dataSize, scale: scale}, nil
+	}
+
+	if scale, ok := timeUnits[name]; ok {
+		return unit{quantity: duration, scale: scale}, nil
+	}
+
+	if u, ok := temperatureUnits[name]; ok {
+		return u, nil
+	}
+
+	if parts := strings.Split(name, "/"); len(parts) == 2 {
+		size, ok := sizeScale(parts[0], pageSize)
+		if !ok {
+			return unit{}, fmt.Errorf("unknown data size unit %q in %q", parts[0], name)
+		}
+		interval, ok := timeUnits[parts[1]]
+		if !ok {
+			return unit{}, fmt.Errorf("unknown time unit %q in %q", parts[1], name)
+		}
+		return unit{quantity: dataRate, scale: size / interval}, nil
+	}
+
+	return unit{}, fmt.Errorf("unknown unit %q", name)
+}
+
+func sizeScale(name string, pageSize int64) (float64, bool) {
+	if name == "pages" {
+		return float64(pageSize), true
+	}
+	scale, ok := sizeUnits[name]
+	return scale, ok
+}
@@ -0,0 +1,191 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+const sampleConfig = `
+  ## Size of a memory page in bytes, used by the "pages" unit.
+  # page_size = 4096
+
+  ## Conversions are applied in order, each field is converted by the first
+  ## conversion matching it.
+  [[processors.units.conversion]]
+    ## Measurements to convert the fields of, glob patterns are accepted.
+    ## All measurements are converted if unset.
+    # measurement = ["mem"]
+
+    ## Fields to convert, glob patterns are accepted.
+    fields = ["*_kb"]
+
+    ## Units to convert the values from and to.  Supported units are:
+    ##   data size   : bit, kbit, Mbit, Gbit, Tbit, B, kB, MB, GB, TB, PB,
+    ##                 KiB, MiB, GiB, TiB, PiB, pages
+    ##   time        : ns, us, ms, s, min, h, d, jiffies
+    ##   temperature : C, F, K
+    ##   data rate   : a data size unit per time unit, such as "MiB/s", or
+    ##                 bps, kbps, Mbps, Gbps
+    from = "KiB"
+    to = "B"
+
+    ## Suffix removed from the field name, and suffix appended to the field
+    ## name, to rename the field with the unit it has been converted to.
+    # trim_suffix = "_kb"
+    # suffix = "_bytes"
+`
+
+type Units struct {
+	PageSize    int64         `toml:"page_size"`
+	Conversions []*Conversion `toml:"conversion"`
+}
+
+type Conversion struct {
+	Measurement []string `toml:"measurement"`
+	Fields      []string `toml:"fields"`
+	From        string   `toml:"from"`
+	To          string   `toml:"to"`
+	TrimSuffix  string   `toml:"trim_suffix"`
+	Suffix      string   `toml:"suffix"`
+
+	measurementFilter filter.Filter
+	fieldFilter       filter.Filter
+	from              unit
+	to                unit
+}
+
+func NewUnits() *Units {
+	return &Units{
+		PageSize: 4096,
+	}
+}
+
+func (u *Units) SampleConfig() string {
+	return sampleConfig
+}
+
+func (u *Units) Description() string {
+	return "Convert the units of field values"
+}
+
+func (u *Units) Init() error {
+	if u.PageSize <= 0 {
+		return fmt.Errorf("invalid page_size: %d", u.PageSize)
+	}
+
+	for _, c := range u.Conversions {
+		if err := c.init(u.PageSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Conversion) init(pageSize int64) error {
+	if len(c.Fields) == 0 {
+		return errors.New("fields must be set")
+	}
+
+	var err error
+	c.measurementFilter, err = filter.Compile(c.Measurement)
+	if err != nil {
+		return err
+	}
+
+	c.fieldFilter, err = filter.Compile(c.Fields)
+	if err != nil {
+		return err
+	}
+
+	c.from, err = parseUnit(c.From, pageSize)
+	if err != nil {
+		return err
+	}
+
+	c.to, err = parseUnit(c.To, pageSize)
+	if err != nil {
+		return err
+	}
+
+	if c.from.quantity != c.to.quantity {
+		return fmt.Errorf("cannot convert %s from %q to %s %q",
+			c.from.quantity, c.From, c.to.quantity, c.To)
+	}
+	return nil
+}
+
+func (u *Units) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	for _, m := range in {
+		u.convert(m)
+	}
+	return in
+}
+
+func (u *Units) convert(m telegraf.Metric) {
+	var conversions []*Conversion
+	for _, c := range u.Conversions {
+		if c.measurementFilter == nil || c.measurementFilter.Match(m.Name()) {
+			conversions = append(conversions, c)
+		}
+	}
+	if len(conversions) == 0 {
+		return
+	}
+
+	// The fields are collected first, as they are renamed while converting.
+	type converted struct {
+		key   string
+		value float64
+		c     *Conversion
+	}
+	var results []converted
+	for _, field := range m.FieldList() {
+		value, ok := toFloat(field.Value)
+		if !ok {
+			continue
+		}
+
+		for _, c := range conversions {
+			if c.fieldFilter.Match(field.Key) {
+				results = append(results, converted{
+					key:   field.Key,
+					value: c.to.fromBase(c.from.toBase(value)),
+					c:     c,
+				})
+				break
+			}
+		}
+	}
+
+	for _, result := range results {
+		key := result.key
+		if result.c.TrimSuffix != "" || result.c.Suffix != "" {
+			m.RemoveField(key)
+			key = strings.TrimSuffix(key, result.c.TrimSuffix) + result.c.Suffix
+		}
+		m.AddField(key, result.value)
+	}
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func init() {
+	processors.Add("units", func() telegraf.Processor {
+		return NewUnits()
+	})
+}
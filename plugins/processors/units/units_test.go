@@ -0,0 +1,192 @@
+package units
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert(t *testing.T) {
+	var tests = []struct {
+		name        string
+		conversions []*Conversion
+		input       telegraf.Metric
+		expected    telegraf.Metric
+	}{
+		{
+			name: "size",
+			conversions: []*Conversion{
+				{Fields: []string{"used"}, From: "KiB", To: "B"},
+			},
+			input: testutil.MustMetric("mem",
+				map[string]string{},
+				map[string]interface{}{"used": int64(2), "total": int64(4)},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("mem",
+				map[string]string{},
+				map[string]interface{}{"used": 2048.0, "total": int64(4)},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "pages",
+			conversions: []*Conversion{
+				{Fields: []string{"*"}, From: "pages", To: "MiB"},
+			},
+			input: testutil.MustMetric("mem",
+				map[string]string{},
+				map[string]interface{}{"free": uint64(512), "state": "ok"},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("mem",
+				map[string]string{},
+				map[string]interface{}{"free": 2.0, "state": "ok"},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "time with rename",
+			conversions: []*Conversion{
+				{Fields: []string{"*_ms"}, From: "ms", To: "s", TrimSuffix: "_ms", Suffix: "_seconds"},
+			},
+			input: testutil.MustMetric("http",
+				map[string]string{},
+				map[string]interface{}{"response_time_ms": 1500.0},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("http",
+				map[string]string{},
+				map[string]interface{}{"response_time_seconds": 1.5},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "jiffies",
+			conversions: []*Conversion{
+				{Fields: []string{"utime"}, From: "jiffies", To: "ms"},
+			},
+			input: testutil.MustMetric("procstat",
+				map[string]string{},
+				map[string]interface{}{"utime": int64(250)},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("procstat",
+				map[string]string{},
+				map[string]interface{}{"utime": 2500.0},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "temperature",
+			conversions: []*Conversion{
+				{Fields: []string{"temp"}, From: "F", To: "C"},
+			},
+			input: testutil.MustMetric("sensors",
+				map[string]string{},
+				map[string]interface{}{"temp": 212.0},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("sensors",
+				map[string]string{},
+				map[string]interface{}{"temp": 100.0},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "rate",
+			conversions: []*Conversion{
+				{Fields: []string{"speed"}, From: "Mbps", To: "kB/s"},
+			},
+			input: testutil.MustMetric("net",
+				map[string]string{},
+				map[string]interface{}{"speed": int64(8)},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("net",
+				map[string]string{},
+				map[string]interface{}{"speed": 1000.0},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "measurement filter",
+			conversions: []*Conversion{
+				{Measurement: []string{"disk*"}, Fields: []string{"*"}, From: "B", To: "kB"},
+			},
+			input: testutil.MustMetric("mem",
+				map[string]string{},
+				map[string]interface{}{"used": int64(2000)},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("mem",
+				map[string]string{},
+				map[string]interface{}{"used": int64(2000)},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "first matching conversion",
+			conversions: []*Conversion{
+				{Fields: []string{"used"}, From: "B", To: "kB"},
+				{Fields: []string{"*"}, From: "B", To: "MB"},
+			},
+			input: testutil.MustMetric("mem",
+				map[string]string{},
+				map[string]interface{}{"used": int64(2000), "free": int64(3000000)},
+				time.Unix(0, 0),
+			),
+			expected: testutil.MustMetric("mem",
+				map[string]string{},
+				map[string]interface{}{"used": 2.0, "free": 3.0},
+				time.Unix(0, 0),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := NewUnits()
+			plugin.Conversions = tt.conversions
+			require.NoError(t, plugin.Init())
+
+			actual := plugin.Apply(tt.input)
+			testutil.RequireMetricsEqual(t, []telegraf.Metric{tt.expected}, actual)
+		})
+	}
+}
+
+func TestInvalidConversion(t *testing.T) {
+	var tests = []struct {
+		name       string
+		conversion *Conversion
+	}{
+		{
+			name:       "no fields",
+			conversion: &Conversion{From: "B", To: "kB"},
+		},
+		{
+			name:       "unknown unit",
+			conversion: &Conversion{Fields: []string{"*"}, From: "B", To: "furlong"},
+		},
+		{
+			name:       "unknown rate unit",
+			conversion: &Conversion{Fields: []string{"*"}, From: "B/fortnight", To: "B/s"},
+		},
+		{
+			name:       "different quantities",
+			conversion: &Conversion{Fields: []string{"*"}, From: "B", To: "s"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := NewUnits()
+			plugin.Conversions = []*Conversion{tt.conversion}
+			require.Error(t, plugin.Init())
+		})
+	}
+}
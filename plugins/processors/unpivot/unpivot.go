@@ -0,0 +1,60 @@
+package unpivot
+
+import (
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+const sampleConfig = `
+  ## Tag to use for the name.
+  tag_key = "name"
+  ## Field to use for the name of the value.
+  value_key = "value"
+`
+
+type Unpivot struct {
+	TagKey   string `toml:"tag_key"`
+	ValueKey string `toml:"value_key"`
+}
+
+func (p *Unpivot) SampleConfig() string {
+	return sampleConfig
+}
+
+func (p *Unpivot) Description() string {
+	return "Rotate multi field metric into several single field metrics"
+}
+
+func (p *Unpivot) Apply(metrics ...telegraf.Metric) []telegraf.Metric {
+	results := make([]telegraf.Metric, 0, len(metrics))
+	for _, original := range metrics {
+		for _, field := range original.FieldList() {
+			m := copyWithoutFields(original)
+			m.AddTag(p.TagKey, field.Key)
+			m.AddField(p.ValueKey, field.Value)
+			results = append(results, m)
+		}
+		original.Drop()
+	}
+	return results
+}
+
+// copyWithoutFields returns a copy of the metric with all fields removed.
+func copyWithoutFields(metric telegraf.Metric) telegraf.Metric {
+	m := metric.Copy()
+
+	keys := make([]string, 0, len(m.FieldList()))
+	for _, field := range m.FieldList() {
+		keys = append(keys, field.Key)
+	}
+	for _, key := range keys {
+		m.RemoveField(key)
+	}
+	return m
+}
+
+func init() {
+	processors.Add("unpivot", func() telegraf.Processor {
+		return &Unpivot{}
+	})
+}
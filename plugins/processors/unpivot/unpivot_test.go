@@ -0,0 +1,126 @@
+package unpivot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpivot(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		unpivot  *Unpivot
+		metrics  []telegraf.Metric
+		expected []telegraf.Metric
+	}{
+		{
+			name: "simple",
+			unpivot: &Unpivot{
+				TagKey:   "name",
+				ValueKey: "value",
+			},
+			metrics: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{},
+					map[string]interface{}{
+						"idle_time": int64(42),
+					},
+					now,
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{
+						"name": "idle_time",
+					},
+					map[string]interface{}{
+						"value": int64(42),
+					},
+					now,
+				),
+			},
+		},
+		{
+			name: "multi fields",
+			unpivot: &Unpivot{
+				TagKey:   "name",
+				ValueKey: "value",
+			},
+			metrics: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{
+						"host": "a",
+					},
+					map[string]interface{}{
+						"idle_time": int64(42),
+						"idle_user": int64(43),
+					},
+					now,
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{
+						"host": "a",
+						"name": "idle_time",
+					},
+					map[string]interface{}{
+						"value": int64(42),
+					},
+					now,
+				),
+				testutil.MustMetric("cpu",
+					map[string]string{
+						"host": "a",
+						"name": "idle_user",
+					},
+					map[string]interface{}{
+						"value": int64(43),
+					},
+					now,
+				),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := tt.unpivot.Apply(tt.metrics...)
+			testutil.RequireMetricsEqual(t, tt.expected, actual, testutil.SortMetrics())
+		})
+	}
+}
+
+func TestUnpivotTracking(t *testing.T) {
+	var delivered bool
+	m, _ := metric.WithTracking(
+		testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{
+				"idle_time": int64(42),
+				"idle_user": int64(43),
+			},
+			time.Now(),
+		),
+		func(telegraf.DeliveryInfo) {
+			delivered = true
+		},
+	)
+
+	unpivot := &Unpivot{
+		TagKey:   "name",
+		ValueKey: "value",
+	}
+	actual := unpivot.Apply(m)
+	require.Len(t, actual, 2)
+	require.False(t, delivered)
+
+	for _, m := range actual {
+		m.Accept()
+	}
+	require.True(t, delivered)
+}
@@ -10,3 +10,29 @@ type Processor interface {
 	// Apply the filter to the given metric.
 	Apply(in ...Metric) []Metric
 }
+
+// StreamingProcessor is a processor that receives a stream of metrics and
+// can emit metrics at any time, making it possible to hold state, buffer
+// metrics or run background work.  Start is called before any metrics are
+// added and Stop is called after the last metric has been added.
+type StreamingProcessor interface {
+	// SampleConfig returns the default configuration of the Processor
+	SampleConfig() string
+
+	// Description returns a one-sentence description on the Processor
+	Description() string
+
+	// Start the StreamingProcessor.  The Accumulator may be retained and
+	// used to emit metrics until Stop returns.
+	Start(acc Accumulator) error
+
+	// Add is called for each metric to be processed.  The metric, and any
+	// new metrics, should be passed downstream by adding them to the
+	// Accumulator or discarded by calling Drop.
+	Add(metric Metric, acc Accumulator) error
+
+	// Stop the StreamingProcessor.  Stop is called once all metrics have
+	// been added and should not return until any outstanding metrics have
+	// been emitted.
+	Stop() error
+}